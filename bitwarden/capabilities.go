@@ -0,0 +1,89 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Capability Feature of the bw CLI, available from a given version
+type Capability struct {
+	Name       string
+	MinVersion *version.Version
+}
+
+func newCapability(name string, minVersion string) Capability {
+	return Capability{Name: name, MinVersion: version.Must(version.NewVersion(minVersion))}
+}
+
+// CapabilityServe bw serve and its unlock endpoint, the provider cannot work without it. Everything else the
+// provider does works with the version introducing it.
+var CapabilityServe = newCapability("bw serve", "1.22.0")
+
+// UnsupportedError Returned when the installed bw CLI is too old for an operation
+type UnsupportedError struct {
+	Capability Capability
+	Version    *version.Version
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf(
+		"bw >= %s is required for %s, the installed bw is %s",
+		e.Capability.MinVersion,
+		e.Capability.Name,
+		e.Version,
+	)
+}
+
+// versionLine A line printing only a version, e.g. "2024.6.0" or "1.22.1"
+var versionLine = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// parseCLIVersion Finds the version in the output of "bw --version", which may come with update notices
+// or deprecation warnings
+func parseCLIVersion(output string) (*version.Version, error) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if versionLine.MatchString(line) {
+			return version.NewVersion(line)
+		}
+	}
+
+	return nil, fmt.Errorf("could not find the version of bw in its output\n%s", output)
+}
+
+// detectVersion Runs "bw --version" and makes sure the CLI is recent enough for the provider to work
+func (c *Client) detectVersion(ctx context.Context) error {
+	stdout, stderr, err := RunCommandOutput(ctx, nil, c.executable(), "--version")
+	if err != nil {
+		return fmt.Errorf("error running %s --version\n%s%s\n%s", c.executable(), stdout, stderr, err)
+	}
+
+	c.version, err = parseCLIVersion(stdout + "\n" + stderr)
+	if err != nil {
+		return err
+	}
+
+	return c.require(CapabilityServe)
+}
+
+// Version Version of the bw CLI used by the client
+func (c *Client) Version() *version.Version {
+	return c.version
+}
+
+// Supports Whether the bw CLI used by the client has a capability
+func (c *Client) Supports(capability Capability) bool {
+	return c.version != nil && !c.version.LessThan(capability.MinVersion)
+}
+
+// require Returns an UnsupportedError when the bw CLI used by the client lacks a capability
+func (c *Client) require(capability Capability) error {
+	if c.Supports(capability) {
+		return nil
+	}
+
+	return &UnsupportedError{Capability: capability, Version: c.version}
+}
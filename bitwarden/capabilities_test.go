@@ -0,0 +1,52 @@
+package bitwarden
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestParseCLIVersion(t *testing.T) {
+	for output, expected := range map[string]string{
+		"1.22.0\n":   "1.22.0",
+		"2024.6.0\n": "2024.6.0",
+		"A new version is available: 2024.7.0 (current: 2024.6.0)\n2024.6.0\n":                     "2024.6.0",
+		"(node:1234) [DEP0040] DeprecationWarning: The `punycode` module is deprecated.\n2024.9.0": "2024.9.0",
+		"2023.12.1-beta.1": "2023.12.1-beta.1",
+	} {
+		parsed, err := parseCLIVersion(output)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", output, err)
+			continue
+		}
+		if parsed.String() != expected {
+			t.Errorf("expected version %s for %q, got %s", expected, output, parsed)
+		}
+	}
+
+	if _, err := parseCLIVersion("command not found\n"); err == nil {
+		t.Error("expected an error when the output holds no version")
+	}
+}
+
+func TestClientRequiresCapabilities(t *testing.T) {
+	c := &Client{version: version.Must(version.NewVersion("1.21.1"))}
+
+	if c.Supports(CapabilityServe) {
+		t.Error("expected bw serve to be unsupported by bw 1.21.1")
+	}
+
+	var unsupported *UnsupportedError
+	if err := c.require(CapabilityServe); !errors.As(err, &unsupported) {
+		t.Fatalf("expected an UnsupportedError, got %v", err)
+	}
+	if unsupported.Error() != "bw >= 1.22.0 is required for bw serve, the installed bw is 1.21.1" {
+		t.Errorf("unexpected message: %s", unsupported.Error())
+	}
+
+	c.version = version.Must(version.NewVersion("2024.6.0"))
+	if err := c.require(CapabilityServe); err != nil {
+		t.Errorf("expected bw serve to be supported by bw 2024.6.0, got %v", err)
+	}
+}
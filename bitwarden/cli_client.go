@@ -0,0 +1,359 @@
+package bitwarden
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// sessionEnv Environment variable the bw CLI reads the session key from
+const sessionEnv = "BW_SESSION"
+
+// ErrCannotLock Returned by Lock when the vault was unlocked with a session key and no master password, locking it
+// would leave the rest of the run without a way to unlock it again
+var ErrCannotLock = errors.New("the vault was unlocked with a session key and cannot be unlocked again without " +
+	"the master password, it is left unlocked")
+
+// CLIClient Vault running one bw command per operation with a session key, for machines where bw serve cannot
+// listen on a port
+type CLIClient struct {
+	*Client
+
+	// sessionMu Guards Session, held for reading while a command runs with it
+	sessionMu sync.RWMutex
+	Session   string
+}
+
+var _ Vault = (*CLIClient)(nil)
+
+// NewCLIClient Uses the given session key, or unlocks the vault with the master password when there is none
+func NewCLIClient(ctx context.Context, config ClientConfig, session string) (*CLIClient, error) {
+	c, err := NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	cli := &CLIClient{Client: c, Session: session}
+	if cli.Session == "" {
+		err = cli.unlock(ctx)
+		if err != nil {
+			_ = c.release(ctx)
+			return nil, err
+		}
+	}
+
+	return cli, nil
+}
+
+// Close Locks the vault with the session key when configured to, then closes the underlying client
+func (c *CLIClient) Close(ctx context.Context) error {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	var lockErr error
+	if c.LockOnExit && c.Session != "" {
+		_, lockErr = c.runWithSession(ctx, c.Session, "", "lock")
+		c.Session = ""
+	}
+
+	return firstError([]error{lockErr, c.Client.Close(ctx)})
+}
+
+// Lock Locks the vault, the next command unlocks it again with the master password. A session key given without
+// master password is left alone with ErrCannotLock, the vault could not be unlocked again for the rest of the run.
+func (c *CLIClient) Lock(ctx context.Context) error {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	if c.Session == "" {
+		return nil
+	}
+	if c.Password == "" && c.PasswordFile == "" {
+		return ErrCannotLock
+	}
+
+	_, err := c.runWithSession(ctx, c.Session, "", "lock")
+	if err != nil {
+		return err
+	}
+
+	c.Session = ""
+	return nil
+}
+
+// session Returns the session key, unlocking the vault when it was locked. The caller holds sessionMu for reading
+// until it is done with the session key.
+func (c *CLIClient) session(ctx context.Context) (string, error) {
+	for {
+		c.sessionMu.RLock()
+		if c.Session != "" {
+			return c.Session, nil
+		}
+		c.sessionMu.RUnlock()
+
+		var err error
+		c.sessionMu.Lock()
+		if c.Session == "" {
+			err = c.unlock(ctx)
+		}
+		c.sessionMu.Unlock()
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// unlock Gets a session key with the master password
+func (c *CLIClient) unlock(ctx context.Context) error {
+	session, err := c.unlockSession(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.Session = session
+	return nil
+}
+
+// run Runs a bw command with the session key and the given standard input, retrying when BitWarden rate-limits us
+func (c *CLIClient) run(ctx context.Context, input string, args ...string) (string, error) {
+	session, err := c.session(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer c.sessionMu.RUnlock()
+
+	return c.runWithSession(ctx, session, input, args...)
+}
+
+// runWithSession Runs a bw command with the given session key, writing input to its standard input
+func (c *CLIClient) runWithSession(ctx context.Context, session string, input string, args ...string) (string, error) {
+	args = append(args, "--nointeraction")
+
+	var stdout, stderr string
+	var err error
+	for attempt := 0; ; attempt++ {
+		stdout, stderr, err = RunCommandInput(
+			ctx,
+			c.environment(sessionEnv+"="+session),
+			input,
+			c.executable(),
+			args...,
+		)
+		if err == nil {
+			return stdout, nil
+		}
+
+		if isCLINotFound(stdout + stderr) {
+			return "", ErrItemNotFound
+		}
+
+		if ctx.Err() != nil || int64(attempt+1) >= c.Retry.MaxAttempts || !isRateLimited(0, stdout+stderr) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(c.Retry.Backoff(attempt)):
+		}
+	}
+
+	return "", fmt.Errorf("bw %s failed\n%s%s\n%s", args[0], stdout, stderr, err)
+}
+
+// isCLINotFound The CLI prints "Not found." when an object doesn't exist
+func isCLINotFound(output string) bool {
+	return strings.TrimSpace(output) == "Not found."
+}
+
+// encode Base64 encodes a JSON payload, like "bw encode" does, without spawning another process
+func encode(payload interface{}) (string, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(content), nil
+}
+
+// runSynced Runs a command on the vault once synced as the sync policy says. When the item with the given id is not
+// found, the vault is synced and the command run once more.
+func (c *CLIClient) runSynced(ctx context.Context, id string, input string, args ...string) (string, error) {
+	fns := c.syncFunctions()
+	err := c.applySyncPolicy(ctx, fns)
+	if err != nil {
+		return "", err
+	}
+
+	var out string
+	err = c.withResync(ctx, fns, func() error {
+		var runErr error
+		out, runErr = c.run(ctx, input, args...)
+		if errors.Is(runErr, ErrItemNotFound) {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+		}
+		return runErr
+	})
+
+	return out, err
+}
+
+func (c *CLIClient) syncFunctions() syncFunctions {
+	return syncFunctions{
+		sync: func(ctx context.Context) error {
+			_, err := c.run(ctx, "", "sync")
+			return err
+		},
+		lastSync: c.lastSync,
+	}
+}
+
+// lastSync Date of the last sync of the vault, as reported by bw status
+func (c *CLIClient) lastSync(ctx context.Context) (*time.Time, error) {
+	out, err := c.run(ctx, "", "status")
+	if err != nil {
+		return nil, err
+	}
+
+	var status CLIStatus
+	err = json.Unmarshal([]byte(out), &status)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the output of bw status\n%s\n%s", out, err)
+	}
+
+	return parseLastSync(status.LastSync)
+}
+
+// runItem Runs a command printing an item
+func (c *CLIClient) runItem(ctx context.Context, id string, input string, args ...string) (*Item, error) {
+	out, err := c.runSynced(ctx, id, input, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Item
+	err = json.Unmarshal([]byte(out), &item)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the item printed by bw %s\n%s", args[0], err)
+	}
+
+	// This is a fix for BW cli that returns duplicated values for collectionIDs
+	item.CollectionIDs = lo.Uniq[string](item.CollectionIDs)
+
+	return &item, nil
+}
+
+// Sync Syncs the vault whatever the sync policy
+func (c *CLIClient) Sync(ctx context.Context) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	return c.forceSync(ctx, c.syncFunctions())
+}
+
+func (c *CLIClient) CreateItem(ctx context.Context, item ItemCreate) (*Item, error) {
+	encoded, err := encode(item)
+	if err != nil {
+		return nil, err
+	}
+
+	// The item holds the notes, bw reads it from its standard input when the argument is left out
+	return c.runItem(ctx, "", encoded, "create", "item")
+}
+
+func (c *CLIClient) GetItem(ctx context.Context, id string) (*Item, error) {
+	return c.runItem(ctx, id, "", "get", "item", id)
+}
+
+func (c *CLIClient) UpdateItem(ctx context.Context, id string, item ItemCreate) (*Item, error) {
+	encoded, err := encode(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.runItem(ctx, id, encoded, "edit", "item", id)
+}
+
+func (c *CLIClient) UpdateItemCollections(ctx context.Context, id string, collectionIDs []string) (*Item, error) {
+	encoded, err := encode(collectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.runItem(ctx, id, encoded, "edit", "item-collections", id)
+}
+
+func (c *CLIClient) MoveItem(ctx context.Context, id string, organizationId string, collectionIDs []string) error {
+	encoded, err := encode(collectionIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.runSynced(ctx, id, encoded, "move", id, organizationId)
+	return err
+}
+
+func (c *CLIClient) DeleteItem(ctx context.Context, id string) error {
+	_, err := c.runSynced(ctx, id, "", "delete", "item", id)
+	return err
+}
+
+func (c *CLIClient) RestoreItem(ctx context.Context, id string) error {
+	_, err := c.runSynced(ctx, id, "", "restore", "item", id)
+	return err
+}
+
+// listCLIObjects Runs "bw list" for the given object type
+func listCLIObjects[T any](ctx context.Context, c *CLIClient, object string, args ...string) ([]T, error) {
+	out, err := c.runSynced(ctx, "", "", append([]string{"list", object}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded []T
+	err = json.Unmarshal([]byte(out), &decoded)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the %s printed by bw list\n%s", object, err)
+	}
+
+	return decoded, nil
+}
+
+func (c *CLIClient) ListItems(
+	ctx context.Context,
+	organizationId string,
+	collectionId string,
+	search string,
+) ([]Item, error) {
+	var args []string
+	if organizationId != "" {
+		args = append(args, "--organizationid", organizationId)
+	}
+	if collectionId != "" {
+		args = append(args, "--collectionid", collectionId)
+	}
+	if search != "" {
+		args = append(args, "--search", search)
+	}
+
+	return listCLIObjects[Item](ctx, c, "items", args...)
+}
+
+func (c *CLIClient) ListFolders(ctx context.Context) ([]Folder, error) {
+	return listCLIObjects[Folder](ctx, c, "folders")
+}
+
+func (c *CLIClient) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	return listCLIObjects[Organization](ctx, c, "organizations")
+}
+
+func (c *CLIClient) ListCollections(ctx context.Context, organizationId string) ([]Collection, error) {
+	return listCLIObjects[Collection](ctx, c, "collections", "--organizationid", organizationId)
+}
@@ -0,0 +1,171 @@
+package bitwarden_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"terraform-bitwarden-sync/bitwarden"
+	"terraform-bitwarden-sync/internal/bwtest"
+)
+
+func TestCLIClientItemLifecycle(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	org := env.Vault.AddOrganization("Org")
+	collection := env.Vault.AddCollection(org.ID, "Collection")
+
+	client, err := bitwarden.NewCLIClient(ctx, bitwarden.ClientConfig{Retry: bitwarden.DefaultRetryPolicy}, bwtest.SessionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note", Notes: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, err = client.UpdateItem(ctx, item.ID, bitwarden.ItemCreate{Type: 2, Name: "Note", Notes: "new secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Notes != "new secret" {
+		t.Errorf("expected notes %q, got %q", "new secret", item.Notes)
+	}
+
+	if err := client.MoveItem(ctx, item.ID, org.ID, []string{collection.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err = client.GetItem(ctx, item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.OrganizationId != org.ID {
+		t.Errorf("expected the item to be moved to %s, got %s", org.ID, item.OrganizationId)
+	}
+
+	collections, err := client.ListCollections(ctx, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collections) != 1 || collections[0].ID != collection.ID {
+		t.Errorf("expected the collection of %s, got %#v", org.ID, collections)
+	}
+
+	items, err := client.ListItems(ctx, org.ID, collection.ID, "note")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ID != item.ID {
+		t.Errorf("expected the item in %s, got %#v", collection.ID, items)
+	}
+
+	other := env.Vault.AddCollection(org.ID, "Other")
+	item, err = client.UpdateItemCollections(ctx, item.ID, []string{collection.ID, other.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(item.CollectionIDs) != 2 {
+		t.Errorf("expected the item to be in 2 collections, got %v", item.CollectionIDs)
+	}
+
+	if err := client.DeleteItem(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RestoreItem(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	env.Vault.PurgeItem(item.ID)
+	if _, err := client.GetItem(ctx, item.ID); !errors.Is(err, bitwarden.ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
+func TestCLIClientUnlocksWithPassword(t *testing.T) {
+	ctx := context.Background()
+	bwtest.NewEnvironment(t)
+
+	client, err := bitwarden.NewCLIClient(ctx, bitwarden.ClientConfig{Password: bwtest.Password}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.Session != bwtest.SessionKey {
+		t.Errorf("expected session key %q, got %q", bwtest.SessionKey, client.Session)
+	}
+
+	if err := client.Sync(ctx); err != nil {
+		t.Error(err)
+	}
+
+	_, err = bitwarden.NewCLIClient(ctx, bitwarden.ClientConfig{Password: "wrong"}, "")
+	if err == nil {
+		t.Error("expected unlocking with a wrong password to fail")
+	}
+}
+
+func TestCLIClientUnlocksWithPasswordFile(t *testing.T) {
+	bwtest.NewEnvironment(t)
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte(bwtest.Password+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := bitwarden.NewCLIClient(
+		context.Background(),
+		bitwarden.ClientConfig{Password: "not used", PasswordFile: path},
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.Session != bwtest.SessionKey {
+		t.Errorf("expected session key %q, got %q", bwtest.SessionKey, client.Session)
+	}
+}
+
+func TestCLIClientLockUnlocksOnNextCommand(t *testing.T) {
+	ctx := context.Background()
+	bwtest.NewEnvironment(t)
+
+	client, err := bitwarden.NewCLIClient(ctx, bitwarden.ClientConfig{Password: bwtest.Password}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Lock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if client.Session != "" {
+		t.Errorf("expected the session key to be forgotten, got %q", client.Session)
+	}
+
+	if err := client.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if client.Session != bwtest.SessionKey {
+		t.Errorf("expected session key %q, got %q", bwtest.SessionKey, client.Session)
+	}
+}
+
+func TestCLIClientLockKeepsGivenSession(t *testing.T) {
+	ctx := context.Background()
+	bwtest.NewEnvironment(t)
+
+	client, err := bitwarden.NewCLIClient(ctx, bitwarden.ClientConfig{}, bwtest.SessionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Lock(ctx); !errors.Is(err, bitwarden.ErrCannotLock) {
+		t.Fatalf("expected ErrCannotLock, got %v", err)
+	}
+	if client.Session != bwtest.SessionKey {
+		t.Errorf("expected session key %q to be kept without master password, got %q", bwtest.SessionKey, client.Session)
+	}
+}
@@ -1,6 +1,7 @@
 package bitwarden
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -30,6 +32,13 @@ type ItemLogin struct {
 	PasswordRevisionDate string         `json:"passwordRevisionDate"`
 }
 
+// ItemField Custom field of an item, of type 0 (text), 1 (hidden), 2 (boolean) or 3 (linked)
+type ItemField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
 type ItemSecureNote struct {
 	Type int `json:"type"`
 }
@@ -46,9 +55,16 @@ type Item struct {
 	Notes          string         `json:"notes"`
 	Favorite       bool           `json:"favorite"`
 	Login          ItemLogin      `json:"login"`
+	Fields         []ItemField    `json:"fields"`
 	SecureNote     ItemSecureNote `json:"secureNote"`
 	CollectionIDs  []string       `json:"collectionIds"`
 	RevisionDate   string         `json:"revisionDate"`
+	DeletedDate    string         `json:"deletedDate"`
+}
+
+// InTrash Items deleted from the web vault are kept in the trash, with their deletion date set
+func (item Item) InTrash() bool {
+	return item.DeletedDate != ""
 }
 
 type ItemResponse struct {
@@ -76,30 +92,54 @@ type BadRequestMessage struct {
 	Message string `json:"message"`
 }
 
+// ErrItemNotFound Returned when the requested item does not exist (anymore) in the vault
+var ErrItemNotFound = errors.New("item not found")
+
+// isNotFound bw serve answers with a 400 "Not found." message rather than a 404 for unknown items
+func isNotFound(statusCode int, body string) bool {
+	if statusCode == http.StatusNotFound {
+		return true
+	}
+
+	var message BadRequestMessage
+	if err := json.Unmarshal([]byte(body), &message); err != nil {
+		return false
+	}
+
+	return strings.EqualFold(strings.TrimSpace(message.Message), "Not found.")
+}
+
+// Types of the items in BitWarden
+const (
+	loginItemType      = 1
+	secureNoteItemType = 2
+)
+
 func PrepareSecureNoteCreate(secureNote SecureNote) ItemCreate {
 	var folderId *string = nil
-	if !secureNote.FolderID.Null {
-		folderId = &secureNote.FolderID.Value
+	if !secureNote.FolderID.IsNull() {
+		folder := secureNote.FolderID.ValueString()
+		folderId = &folder
 	}
 
-	var favorite = false
-	if !secureNote.Favorite.Null {
-		favorite = secureNote.Favorite.Value
+	notes := secureNote.Notes.ValueString()
+	if secureNote.Notes.IsNull() {
+		notes = secureNote.NotesWO.ValueString()
 	}
 
 	var reprompt = 0
-	if !secureNote.Reprompt.Null && secureNote.Reprompt.Value {
+	if secureNote.Reprompt.ValueBool() {
 		reprompt = 1
 	}
 
 	return ItemCreate{
-		OrganizationId: secureNote.OrganizationId.Value,
+		OrganizationId: secureNote.OrganizationId.ValueString(),
 		CollectionIDs:  secureNote.CollectionIDs,
 		FolderID:       folderId,
-		Type:           2,
-		Name:           secureNote.Name.Value,
-		Notes:          secureNote.Notes.Value,
-		Favorite:       favorite,
+		Type:           secureNoteItemType,
+		Name:           secureNote.Name.ValueString(),
+		Notes:          notes,
+		Favorite:       secureNote.Favorite.ValueBool(),
 		Fields:         nil,
 		Login:          nil,
 		SecureNote:     &ItemSecureNote{Type: 0},
@@ -109,9 +149,49 @@ func PrepareSecureNoteCreate(secureNote SecureNote) ItemCreate {
 	}
 }
 
-type Client struct {
+// ClientConfig Settings of a Client, as configured on the provider
+type ClientConfig struct {
 	Password string
-	Port     int64
+	// PasswordFile File the password was read from, if any, so that bw unlock can read it itself
+	PasswordFile string
+	Port         int64
+	Retry        RetryPolicy
+	// ClientID and ClientSecret API key to log in with, in a private CLI data directory
+	ClientID     string
+	ClientSecret string
+	// ServerURL BitWarden server to use, leave empty to use the one the CLI is configured with
+	ServerURL string
+	// Executable Path or name of the bw CLI, "bw" from the PATH when empty
+	Executable string
+	SyncPolicy SyncPolicy
+	// LockOnExit and LogoutOnExit What to do with the vault when the provider shuts down
+	LockOnExit   bool
+	LogoutOnExit bool
+}
+
+type Client struct {
+	ClientConfig
+
+	// appDataDir Private CLI data directory, empty when using a bw serve started outside of the provider
+	appDataDir string
+	// globalSessionLock Lock file claiming the copy of the global CLI state, empty when the client has none
+	globalSessionLock string
+	loggedIn          bool
+	// lifetime Context of the processes outliving the operation starting them, cancelled on release
+	lifetime    context.Context
+	endLifetime context.CancelFunc
+	// version Version of the bw CLI, detected when creating the client
+	version *version.Version
+
+	// serveMu Serializes the calls to bw serve, which is started and unlocked on first use and kept until Close
+	serveMu sync.Mutex
+	serve   *bwServeClient
+	// serveLocked The vault of serve was locked by Lock, the next call unlocks it again
+	serveLocked bool
+
+	// syncMu Guards synced, which tells whether the provider synced the vault during this run
+	syncMu sync.Mutex
+	synced bool
 }
 
 type bwServeClient struct {
@@ -119,7 +199,62 @@ type bwServeClient struct {
 	restClient *resty.Client
 }
 
-func bitwardenServeAndUnlock(c *Client) (*bwServeClient, error) {
+// acquireServe Returns the bw serve of the client, starting it on first use, and syncs the vault as the sync
+// policy says. The caller has exclusive use of it until releaseServe.
+func (c *Client) acquireServe(ctx context.Context) (*bwServeClient, error) {
+	bwClient, err := c.startServe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.applySyncPolicy(ctx, bwClient.syncFunctions())
+	if err != nil {
+		c.releaseServe()
+		return nil, err
+	}
+
+	return bwClient, nil
+}
+
+// startServe Same as acquireServe, without syncing
+func (c *Client) startServe(ctx context.Context) (*bwServeClient, error) {
+	c.serveMu.Lock()
+
+	if c.serve == nil {
+		bwClient, err := bitwardenServeAndUnlock(ctx, c)
+		if err != nil {
+			c.serveMu.Unlock()
+			return nil, err
+		}
+		c.serve = bwClient
+	} else if c.serveLocked {
+		err := c.serve.unlock(ctx, c.Password)
+		if err != nil {
+			c.serveMu.Unlock()
+			return nil, err
+		}
+		c.serveLocked = false
+	}
+
+	return c.serve, nil
+}
+
+func (c *Client) releaseServe() {
+	c.serveMu.Unlock()
+}
+
+// stopServe Stops the bw serve of the client, if it started one
+func (c *Client) stopServe() {
+	c.serveMu.Lock()
+	defer c.serveMu.Unlock()
+
+	if c.serve != nil {
+		c.serve.Close()
+		c.serve = nil
+	}
+}
+
+func bitwardenServeAndUnlock(ctx context.Context, c *Client) (*bwServeClient, error) {
 	bwClient := bwServeClient{}
 
 	var bwPort string
@@ -136,7 +271,16 @@ func bitwardenServeAndUnlock(c *Client) (*bwServeClient, error) {
 			return nil, err
 		}
 
-		bwClient.Command = exec.Command("bw", "serve", "--port", bwPort)
+		// The password never reaches bw serve, which is started with the session key of the unlocked vault
+		session, err := c.unlockSession(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// The serve process outlives the operation starting it, it is killed when the client is released
+		bwClient.Command = exec.CommandContext(c.lifetime, c.executable(), "serve", "--port", bwPort)
+		bwClient.Command.Env = c.environment(sessionEnv + "=" + session)
+		bindToProvider(bwClient.Command)
 		if err := bwClient.Command.Start(); err != nil {
 			return nil, err
 		}
@@ -146,25 +290,26 @@ func bitwardenServeAndUnlock(c *Client) (*bwServeClient, error) {
 
 	bwClient.restClient = resty.New()
 	bwClient.restClient.SetBaseURL("http://localhost:" + bwPort)
-	bwClient.restClient.SetRetryCount(10).SetRetryWaitTime(5 * time.Second).AddRetryCondition(
-		func(response *resty.Response, err error) bool {
-			return response.StatusCode() == http.StatusBadGateway || response.StatusCode() == http.StatusBadRequest
-		},
-	)
+	c.Retry.configure(bwClient.restClient)
 
 	bwTimedout := true
 	var errorResp *resty.Response
 	var bwErr error
 	start := time.Now()
 	for time.Since(start) < time.Second*time.Duration(10) {
-		errorResp, bwErr = bwClient.restClient.R().Get("/status")
+		errorResp, bwErr = bwClient.restClient.R().SetContext(ctx).Get("/status")
 
 		if bwErr == nil && errorResp.StatusCode() == 200 {
 			bwTimedout = false
 			break
 		}
-		time.Sleep(time.Second)
 
+		select {
+		case <-ctx.Done():
+			bwClient.Close()
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
 	}
 	if bwTimedout {
 		bwClient.Close()
@@ -175,7 +320,7 @@ func bitwardenServeAndUnlock(c *Client) (*bwServeClient, error) {
 			)
 		} else if errorResp != nil {
 			return nil, fmt.Errorf(
-				"bitwarden serve did not answer in a reasonable time http error [%s] %s",
+				"bitwarden serve did not answer in a reasonable time http error [%d] %s",
 				errorResp.StatusCode(),
 				errorResp.Body(),
 			)
@@ -184,34 +329,82 @@ func bitwardenServeAndUnlock(c *Client) (*bwServeClient, error) {
 		}
 	}
 
-	resp, err := bwClient.restClient.R().SetBody(map[string]string{"password": c.Password}).Post("/unlock")
+	if bwClient.Command == nil {
+		err := bwClient.unlock(ctx, c.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &bwClient, nil
+}
+
+// unlockSession Unlocks the vault with the master password, which is only handed to the unlock command, and returns
+// the session key
+func (c *Client) unlockSession(ctx context.Context) (string, error) {
+	env := c.environment()
+	args := []string{"unlock", "--raw", "--nointeraction"}
+	if c.PasswordFile != "" {
+		args = append(args, "--passwordfile", c.PasswordFile)
+	} else {
+		env = c.environment(passwordEnv + "=" + c.Password)
+		args = append(args, "--passwordenv", passwordEnv)
+	}
+
+	stdout, stderr, err := RunCommandOutput(ctx, env, c.executable(), args...)
 	if err != nil {
-		bwClient.Close()
-		return nil, err
+		return "", fmt.Errorf("error unlocking bitwarden\n%s%s\n%s", stdout, stderr, err)
 	}
 
-	if resp.StatusCode() != 200 {
-		bwClient.Close()
-		return nil, fmt.Errorf("error unlocking bitwarden\n%s", resp.Body())
+	return strings.TrimSpace(stdout), nil
+}
+
+// unlock Unlocks a bw serve run outside of the provider, it has no other way to get the password than its API.
+// The password is only sent when the vault is locked.
+func (bwClient *bwServeClient) unlock(ctx context.Context, password string) error {
+	status, err := bwClient.status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Status == "unlocked" {
+		return nil
 	}
 
-	err = bwClient.Sync()
+	resp, err := bwClient.restClient.R().SetContext(ctx).SetBody(map[string]string{"password": password}).Post("/unlock")
 	if err != nil {
-		bwClient.Close()
-		return nil, err
+		return err
 	}
 
-	return &bwClient, nil
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("error unlocking bitwarden\n%s", resp.Body())
+	}
+
+	return nil
+}
+
+// executable bw CLI run by the client
+func (c *Client) executable() string {
+	if c.Executable == "" {
+		return "bw"
+	}
+
+	return c.Executable
+}
+
+// serveURL URL of the bw serve configured with bw_serve_port
+func (c *Client) serveURL() string {
+	return "http://localhost:" + strconv.Itoa(int(c.Port))
 }
 
 func (bwClient *bwServeClient) Close() {
 	if bwClient.Command != nil {
 		_ = bwClient.Command.Process.Kill()
+		_ = bwClient.Command.Wait()
 	}
 }
 
-func (bwClient *bwServeClient) Sync() error {
-	resp, err := bwClient.restClient.R().Post("/sync")
+func (bwClient *bwServeClient) Sync(ctx context.Context) error {
+	resp, err := bwClient.restClient.R().SetContext(ctx).Post("/sync")
 	if err != nil {
 		return err
 	}
@@ -223,74 +416,100 @@ func (bwClient *bwServeClient) Sync() error {
 	return nil
 }
 
-func NewClient(password string, port int64) (*Client, error) {
-	c := Client{Password: password, Port: port}
-
-	out, err := RunCommand("bw", "--version")
+// status Status of the vault, as reported by bw serve
+func (bwClient *bwServeClient) status(ctx context.Context) (*CLIStatus, error) {
+	resp, err := bwClient.restClient.R().SetContext(ctx).Get("/status")
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("%s\n%s", out, err))
+		return nil, err
 	}
 
-	requiredVersion, err := version.NewVersion("1.22.0")
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("error fetching the status of bitwarden serve\n%s", resp.Body())
+	}
+
+	var decoded serveStatusResponse
+	err = json.Unmarshal(resp.Body(), &decoded)
 	if err != nil {
 		return nil, err
 	}
 
-	clientVersion, err := version.NewVersion(strings.TrimSpace(strings.ReplaceAll(out, "\n", "")))
+	return &decoded.Data.Template, nil
+}
+
+// lastSync Date of the last sync of the vault, as reported by bw serve
+func (bwClient *bwServeClient) lastSync(ctx context.Context) (*time.Time, error) {
+	status, err := bwClient.status(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if clientVersion.LessThan(requiredVersion) {
-		return nil, fmt.Errorf("bitwarden client version(%s) must be equal or greater than 1.22.0", out)
-	}
+	return parseLastSync(status.LastSync)
+}
 
-	return &c, nil
+func (bwClient *bwServeClient) syncFunctions() syncFunctions {
+	return syncFunctions{sync: bwClient.Sync, lastSync: bwClient.lastSync}
 }
 
-func (c *Client) CreateSecureNote(secureNote SecureNote) (*Item, error) {
-	createPayload := PrepareSecureNoteCreate(secureNote)
+func NewClient(ctx context.Context, config ClientConfig) (*Client, error) {
+	c := Client{ClientConfig: config}
+	c.lifetime, c.endLifetime = context.WithCancel(context.Background())
 
-	bwClient, err := bitwardenServeAndUnlock(c)
+	err := c.detectVersion(ctx)
 	if err != nil {
+		c.endLifetime()
 		return nil, err
 	}
 
-	resp, err := bwClient.restClient.R().SetBody(createPayload).Post("/object/item")
-	if err != nil {
-		return nil, err
+	// A bw serve started outside of the provider comes with its own CLI data directory
+	if c.Port == 0 {
+		err = c.isolate()
+		if err != nil {
+			_ = c.release(ctx)
+			return nil, err
+		}
 	}
-	bwClient.Close()
 
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("bitwarden error when creating secure note\n%s", resp.Body())
+	if c.ClientID != "" {
+		err = c.loginWithAPIKey(ctx)
+	} else if c.ServerURL != "" {
+		err = c.checkServer(ctx)
 	}
-
-	var decoded ItemResponse
-	err = json.Unmarshal(resp.Body(), &decoded)
 	if err != nil {
+		_ = c.release(ctx)
 		return nil, err
 	}
 
-	return &decoded.Data, nil
+	return &c, nil
 }
 
-func (c *Client) UpdateSecureNote(id string, secureNote SecureNote) (*Item, error) {
-	updatePayload := PrepareSecureNoteCreate(secureNote)
+// Sync Syncs the vault whatever the sync policy, starting and unlocking bw serve if needed
+func (c *Client) Sync(ctx context.Context) error {
+	bwClient, err := c.startServe(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.releaseServe()
+
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
 
-	bwClient, err := bitwardenServeAndUnlock(c)
+	return c.forceSync(ctx, bwClient.syncFunctions())
+}
+
+func (c *Client) CreateItem(ctx context.Context, item ItemCreate) (*Item, error) {
+	bwClient, err := c.acquireServe(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer c.releaseServe()
 
-	resp, err := bwClient.restClient.R().SetBody(updatePayload).Put(fmt.Sprintf("/object/item/%s", id))
+	resp, err := bwClient.restClient.R().SetContext(ctx).SetBody(item).Post("/object/item")
 	if err != nil {
 		return nil, err
 	}
-	bwClient.Close()
 
 	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("bitwarden error when updating secure note\n%s", resp.Body())
+		return nil, fmt.Errorf("bitwarden error when creating item\n%s", resp.Body())
 	}
 
 	var decoded ItemResponse
@@ -302,68 +521,254 @@ func (c *Client) UpdateSecureNote(id string, secureNote SecureNote) (*Item, erro
 	return &decoded.Data, nil
 }
 
-func (c *Client) GetItem(id string) (*Item, error) {
-	bwClient, err := bitwardenServeAndUnlock(c)
+func (c *Client) UpdateItem(ctx context.Context, id string, item ItemCreate) (*Item, error) {
+	bwClient, err := c.acquireServe(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer c.releaseServe()
+
+	var updated *Item
+	err = c.withResync(ctx, bwClient.syncFunctions(), func() error {
+		resp, err := bwClient.restClient.R().SetContext(ctx).SetBody(item).Put(fmt.Sprintf("/object/item/%s", id))
+		if err != nil {
+			return err
+		}
+
+		if isNotFound(resp.StatusCode(), string(resp.Body())) {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+		}
+
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("bitwarden error when updating item\n%s", resp.Body())
+		}
+
+		var decoded ItemResponse
+		err = json.Unmarshal(resp.Body(), &decoded)
+		if err != nil {
+			return err
+		}
+
+		updated = &decoded.Data
+		return nil
+	})
 
-	resp, err := bwClient.restClient.R().Get(fmt.Sprintf("/object/item/%s", id))
+	return updated, err
+}
+
+func (c *Client) GetItem(ctx context.Context, id string) (*Item, error) {
+	bwClient, err := c.acquireServe(ctx)
 	if err != nil {
 		return nil, err
 	}
-	bwClient.Close()
+	defer c.releaseServe()
 
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("bitwarden error when fetching secure note\n%s", resp.Body())
-	}
+	var item *Item
+	err = c.withResync(ctx, bwClient.syncFunctions(), func() error {
+		resp, err := bwClient.restClient.R().SetContext(ctx).Get(fmt.Sprintf("/object/item/%s", id))
+		if err != nil {
+			return err
+		}
 
-	var decoded ItemResponse
-	err = json.Unmarshal(resp.Body(), &decoded)
+		if isNotFound(resp.StatusCode(), string(resp.Body())) {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+		}
+
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("bitwarden error when fetching item\n%s", resp.Body())
+		}
+
+		var decoded ItemResponse
+		err = json.Unmarshal(resp.Body(), &decoded)
+		if err != nil {
+			return err
+		}
+
+		// This is a fix for BW cli that returns duplicated values for collectionIDs
+		decoded.Data.CollectionIDs = lo.Uniq[string](decoded.Data.CollectionIDs)
+
+		item = &decoded.Data
+		return nil
+	})
+
+	return item, err
+}
+
+func (c *Client) UpdateItemCollections(ctx context.Context, id string, collectionIDs []string) (*Item, error) {
+	bwClient, err := c.acquireServe(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer c.releaseServe()
 
-	// This is a fix for BW cli that returns duplicated values for collectionIDs
-	decoded.Data.CollectionIDs = lo.Uniq[string](decoded.Data.CollectionIDs)
+	var updated *Item
+	err = c.withResync(ctx, bwClient.syncFunctions(), func() error {
+		resp, err := bwClient.restClient.R().
+			SetContext(ctx).
+			SetBody(collectionIDs).
+			Put(fmt.Sprintf("/object/item-collections/%s", id))
+		if err != nil {
+			return err
+		}
 
-	return &decoded.Data, nil
+		if isNotFound(resp.StatusCode(), string(resp.Body())) {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+		}
+
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("bitwarden error when updating the collections of item\n%s", resp.Body())
+		}
+
+		var decoded ItemResponse
+		err = json.Unmarshal(resp.Body(), &decoded)
+		if err != nil {
+			return err
+		}
+
+		// This is a fix for BW cli that returns duplicated values for collectionIDs
+		decoded.Data.CollectionIDs = lo.Uniq[string](decoded.Data.CollectionIDs)
+
+		updated = &decoded.Data
+		return nil
+	})
+
+	return updated, err
 }
 
-func (c *Client) MoveItem(id string, newOrgId string) error {
-	bwClient, err := bitwardenServeAndUnlock(c)
+func (c *Client) MoveItem(ctx context.Context, id string, organizationId string, collectionIDs []string) error {
+	bwClient, err := c.acquireServe(ctx)
 	if err != nil {
 		return err
 	}
+	defer c.releaseServe()
 
-	resp, err := bwClient.restClient.R().Post(fmt.Sprintf("/move/%s/%s", id, newOrgId))
+	return c.withResync(ctx, bwClient.syncFunctions(), func() error {
+		resp, err := bwClient.restClient.R().
+			SetContext(ctx).
+			SetBody(collectionIDs).
+			Post(fmt.Sprintf("/move/%s/%s", id, organizationId))
+		if err != nil {
+			return err
+		}
+
+		if isNotFound(resp.StatusCode(), string(resp.Body())) {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+		}
+
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("bitwarden error when moving item\n%s", resp.Body())
+		}
+
+		return nil
+	})
+}
+
+func (c *Client) DeleteItem(ctx context.Context, id string) error {
+	bwClient, err := c.acquireServe(ctx)
 	if err != nil {
 		return err
 	}
-	bwClient.Close()
+	defer c.releaseServe()
 
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("bitwarden error when moving secure note\n%s", resp.Body())
-	}
+	return c.withResync(ctx, bwClient.syncFunctions(), func() error {
+		resp, err := bwClient.restClient.R().SetContext(ctx).Delete(fmt.Sprintf("/object/item/%s", id))
+		if err != nil {
+			return err
+		}
 
-	return nil
+		if isNotFound(resp.StatusCode(), string(resp.Body())) {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+		}
+
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("bitwarden error when deleting item\n%s", resp.Body())
+		}
+
+		return nil
+	})
 }
 
-func (c *Client) DeleteItem(id string) error {
-	bwClient, err := bitwardenServeAndUnlock(c)
+// RestoreItem Brings an item back from the trash
+func (c *Client) RestoreItem(ctx context.Context, id string) error {
+	bwClient, err := c.acquireServe(ctx)
 	if err != nil {
 		return err
 	}
+	defer c.releaseServe()
+
+	return c.withResync(ctx, bwClient.syncFunctions(), func() error {
+		resp, err := bwClient.restClient.R().SetContext(ctx).Post(fmt.Sprintf("/restore/item/%s", id))
+		if err != nil {
+			return err
+		}
+
+		if isNotFound(resp.StatusCode(), string(resp.Body())) {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+		}
+
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("bitwarden error when restoring item\n%s", resp.Body())
+		}
+
+		return nil
+	})
+}
 
-	resp, err := bwClient.restClient.R().Delete(fmt.Sprintf("/object/item/%s", id))
+type listResponse[T any] struct {
+	Data struct {
+		Data []T `json:"data"`
+	} `json:"data"`
+}
+
+// listObjects Fetches one of the /list/object/... endpoints of bw serve
+func listObjects[T any](ctx context.Context, c *Client, object string, query map[string]string) ([]T, error) {
+	bwClient, err := c.acquireServe(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer c.releaseServe()
+
+	resp, err := bwClient.restClient.R().SetContext(ctx).SetQueryParams(query).Get("/list/object/" + object)
+	if err != nil {
+		return nil, err
 	}
-	bwClient.Close()
 
 	if resp.StatusCode() != 200 {
-		return fmt.Errorf("bitwarden error when deleting secure note\n%s", resp.Body())
+		return nil, fmt.Errorf("bitwarden error when listing %s\n%s", object, resp.Body())
 	}
 
-	return nil
+	var decoded listResponse[T]
+	err = json.Unmarshal(resp.Body(), &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoded.Data.Data, nil
+}
+
+func (c *Client) ListItems(ctx context.Context, organizationId string, collectionId string, search string) ([]Item, error) {
+	query := map[string]string{}
+	for param, value := range map[string]string{
+		"organizationId": organizationId,
+		"collectionId":   collectionId,
+		"search":         search,
+	} {
+		if value != "" {
+			query[param] = value
+		}
+	}
+
+	return listObjects[Item](ctx, c, "items", query)
+}
+
+func (c *Client) ListFolders(ctx context.Context) ([]Folder, error) {
+	return listObjects[Folder](ctx, c, "folders", nil)
+}
+
+func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	return listObjects[Organization](ctx, c, "organizations", nil)
+}
+
+func (c *Client) ListCollections(ctx context.Context, organizationId string) ([]Collection, error) {
+	return listObjects[Collection](ctx, c, "collections", map[string]string{"organizationId": organizationId})
 }
@@ -0,0 +1,482 @@
+package bitwarden_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-bitwarden-sync/bitwarden"
+	"terraform-bitwarden-sync/internal/bwtest"
+)
+
+func newTestClient(t *testing.T, env *bwtest.Environment) *bitwarden.Client {
+	t.Helper()
+
+	port, err := strconv.ParseInt(env.Serve.Port(), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retry := bitwarden.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	client, err := bitwarden.NewClient(context.Background(), bitwarden.ClientConfig{
+		Password: bwtest.Password,
+		Port:     port,
+		Retry:    retry,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestClientCreatesItemMentioningRateLimitOnce(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	client := newTestClient(t, env)
+
+	notes := "Too many requests? Slow down, the rate limit is 10 per second"
+	if _, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Runbook", Notes: notes}); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := env.Vault.ListItems(ctx, "", "", "Runbook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Errorf("expected the successful creation not to be retried, got %d items", len(items))
+	}
+}
+
+func TestClientItemLifecycle(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	client := newTestClient(t, env)
+	org := env.Vault.AddOrganization("Org")
+	collection := env.Vault.AddCollection(org.ID, "Collection")
+
+	item, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note", Notes: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, err = client.UpdateItem(ctx, item.ID, bitwarden.ItemCreate{Type: 2, Name: "Note", Notes: "new secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Notes != "new secret" {
+		t.Errorf("expected notes %q, got %q", "new secret", item.Notes)
+	}
+
+	if err := client.MoveItem(ctx, item.ID, org.ID, []string{collection.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err = client.GetItem(ctx, item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.OrganizationId != org.ID || len(item.CollectionIDs) != 1 || item.CollectionIDs[0] != collection.ID {
+		t.Errorf("expected the item to be moved to %s/%s, got %#v", org.ID, collection.ID, item)
+	}
+
+	other := env.Vault.AddCollection(org.ID, "Other")
+	item, err = client.UpdateItemCollections(ctx, item.ID, []string{collection.ID, other.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(item.CollectionIDs) != 2 {
+		t.Errorf("expected the item to be in 2 collections, got %v", item.CollectionIDs)
+	}
+
+	if err := client.DeleteItem(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err = client.GetItem(ctx, item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !item.InTrash() {
+		t.Error("expected the item to be in the trash")
+	}
+
+	if err := client.RestoreItem(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	env.Vault.PurgeItem(item.ID)
+	if _, err := client.GetItem(ctx, item.ID); !errors.Is(err, bitwarden.ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
+func TestClientListObjects(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	client := newTestClient(t, env)
+	org := env.Vault.AddOrganization("Org")
+	env.Vault.AddCollection(org.ID, "Collection")
+	env.Vault.AddCollection(env.Vault.AddOrganization("Other").ID, "Other collection")
+	env.Vault.AddFolder("Folder")
+
+	organizations, err := client.ListOrganizations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(organizations) != 2 {
+		t.Errorf("expected 2 organizations, got %d", len(organizations))
+	}
+
+	collections, err := client.ListCollections(ctx, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collections) != 1 || collections[0].Name != "Collection" {
+		t.Errorf("expected only the collection of %s, got %#v", org.ID, collections)
+	}
+
+	folders, err := client.ListFolders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folders) != 1 || folders[0].Name != "Folder" {
+		t.Errorf("expected a single folder, got %#v", folders)
+	}
+
+	collection := collections[0]
+	for _, name := range []string{"Note", "Other note"} {
+		_, err := client.CreateItem(ctx, bitwarden.ItemCreate{
+			OrganizationId: org.ID,
+			CollectionIDs:  []string{collection.ID},
+			Type:           2,
+			Name:           name,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Personal note"}); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := client.ListItems(ctx, org.ID, collection.ID, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Name != "Other note" {
+		t.Errorf("expected only the other note of %s, got %#v", collection.ID, items)
+	}
+}
+
+func TestClientWrongPassword(t *testing.T) {
+	env := bwtest.NewEnvironment(t)
+	client := newTestClient(t, env)
+	client.Password = "wrong"
+
+	if err := client.Sync(context.Background()); err == nil {
+		t.Error("expected unlocking with a wrong password to fail")
+	}
+}
+
+func TestClientAPIKeyLogin(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	client, err := bitwarden.NewClient(ctx, bitwarden.ClientConfig{
+		Password:     bwtest.Password,
+		Retry:        bitwarden.RetryPolicy{MaxAttempts: 1},
+		ClientID:     bwtest.ClientID,
+		ClientSecret: bwtest.ClientSecret,
+		ServerURL:    "https://vault.bitwarden.eu",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataFiles, _ := filepath.Glob(filepath.Join(tmp, "terraform-provider-bitwarden-*", "data.json"))
+	if len(dataFiles) != 1 {
+		t.Fatalf("expected the login to happen in a private CLI data directory, found %v", dataFiles)
+	}
+
+	data, err := os.ReadFile(dataFiles[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"serverUrl":"https://vault.bitwarden.eu"`) {
+		t.Errorf("expected the CLI to be configured for the EU server before login, got %s", data)
+	}
+
+	if _, err := os.Stat(filepath.Join(env.GlobalAppDataDir, "data.json")); !os.IsNotExist(err) {
+		t.Error("expected the global CLI data directory to be left untouched")
+	}
+
+	// bw serve runs in the private directory, with the session key of the vault unlocked with the master password
+	if _, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	leftovers, _ := filepath.Glob(filepath.Join(tmp, "terraform-provider-bitwarden-*"))
+	if len(leftovers) != 0 {
+		t.Errorf("expected the private CLI data directory to be removed, found %v", leftovers)
+	}
+}
+
+func TestClientAPIKeyLoginFailure(t *testing.T) {
+	bwtest.NewEnvironment(t)
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	_, err := bitwarden.NewClient(context.Background(), bitwarden.ClientConfig{
+		Password:     bwtest.Password,
+		ClientID:     bwtest.ClientID,
+		ClientSecret: "wrong",
+	})
+	if err == nil {
+		t.Fatal("expected the login to fail")
+	}
+
+	leftovers, _ := filepath.Glob(filepath.Join(tmp, "terraform-provider-bitwarden-*"))
+	if len(leftovers) != 0 {
+		t.Errorf("expected the private CLI data directory to be removed, found %v", leftovers)
+	}
+}
+
+func TestClientServerMismatch(t *testing.T) {
+	env := bwtest.NewEnvironment(t)
+	port, _ := strconv.ParseInt(env.Serve.Port(), 10, 64)
+
+	for server, shouldMatch := range map[string]bool{
+		"https://vault.bitwarden.com":   true,
+		"https://bitwarden.com/":        true,
+		"https://vault.bitwarden.eu":    false,
+		"https://bitwarden.example.com": false,
+	} {
+		_, err := bitwarden.NewClient(context.Background(), bitwarden.ClientConfig{
+			Password:  bwtest.Password,
+			Port:      port,
+			ServerURL: server,
+		})
+
+		if shouldMatch && err != nil {
+			t.Errorf("expected %s to match the session of the default server, got %s", server, err)
+		}
+		if !shouldMatch && err == nil {
+			t.Errorf("expected %s not to match the session of the default server", server)
+		}
+	}
+}
+
+func TestClientServerMismatchWithGlobalSession(t *testing.T) {
+	env := bwtest.NewEnvironment(t)
+	err := os.WriteFile(
+		filepath.Join(env.GlobalAppDataDir, "data.json"),
+		[]byte(`{"loggedIn":true,"serverUrl":"https://bitwarden.example.com"}`),
+		0o600,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = bitwarden.NewClient(context.Background(), bitwarden.ClientConfig{
+		Password:  bwtest.Password,
+		ServerURL: "https://vault.bitwarden.eu",
+	})
+	if err == nil || !strings.Contains(err.Error(), "https://bitwarden.example.com") {
+		t.Errorf("expected an error about the server of the global session, got %v", err)
+	}
+
+	_, err = bitwarden.NewClient(context.Background(), bitwarden.ClientConfig{
+		Password:  bwtest.Password,
+		ServerURL: "https://bitwarden.example.com",
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClientReusesServe(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	client := newTestClient(t, env)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if unlocks := env.Serve.Unlocks(); unlocks != 1 {
+		t.Errorf("expected bw serve to be unlocked once, got %d unlocks", unlocks)
+	}
+}
+
+func TestClientsAreIsolated(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	global := []byte(`{"loggedIn":true}`)
+	if err := os.WriteFile(filepath.Join(env.GlobalAppDataDir, "data.json"), global, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	retry := bitwarden.RetryPolicy{MaxAttempts: 1}
+	withAPIKey, err := bitwarden.NewClient(ctx, bitwarden.ClientConfig{
+		Password:     bwtest.Password,
+		Retry:        retry,
+		ClientID:     bwtest.ClientID,
+		ClientSecret: bwtest.ClientSecret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer withAPIKey.Close(ctx)
+
+	withGlobalSession, err := bitwarden.NewClient(ctx, bitwarden.ClientConfig{Password: bwtest.Password, Retry: retry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer withGlobalSession.Close(ctx)
+
+	dirs, _ := filepath.Glob(filepath.Join(tmp, "terraform-provider-bitwarden-*"))
+	if len(dirs) != 2 {
+		t.Fatalf("expected a private CLI data directory per client, found %v", dirs)
+	}
+
+	for _, client := range []*bitwarden.Client{withAPIKey, withGlobalSession} {
+		if _, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Logging out the API key must neither affect the other client nor the global session
+	if err := withAPIKey.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := withGlobalSession.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(env.GlobalAppDataDir, "data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(global) {
+		t.Errorf("expected the global CLI state to be left untouched, got %s", data)
+	}
+}
+
+func TestClientRequiresRecentCLI(t *testing.T) {
+	bwtest.NewEnvironment(t)
+	t.Setenv("FAKE_BW_VERSION", "1.21.0")
+
+	_, err := bitwarden.NewClient(context.Background(), bitwarden.ClientConfig{Password: bwtest.Password})
+
+	var unsupported *bitwarden.UnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Capability.Name != bitwarden.CapabilityServe.Name {
+		t.Errorf("expected bw 1.21.0 to be rejected, got %v", err)
+	}
+}
+
+func TestClientExecutable(t *testing.T) {
+	ctx := context.Background()
+	bwtest.NewEnvironment(t)
+	executable := bwtest.FakeBW(t)
+	t.Setenv("PATH", t.TempDir())
+
+	client, err := bitwarden.NewCLIClient(
+		ctx,
+		bitwarden.ClientConfig{Password: bwtest.Password, Executable: executable},
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close(ctx)
+
+	if client.Version().String() != "2024.6.0" {
+		t.Errorf("expected the version of the fake bw, got %s", client.Version())
+	}
+
+	if _, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientLockUnlocksOnNextOperation(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	client := newTestClient(t, env)
+
+	item, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Lock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if env.Serve.Unlocked() {
+		t.Error("expected bw serve to be locked")
+	}
+
+	if _, err := client.GetItem(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+	if unlocks := env.Serve.Unlocks(); unlocks != 2 {
+		t.Errorf("expected bw serve to be unlocked again, got %d unlocks", unlocks)
+	}
+}
+
+func TestClientStartsServeWithSessionKey(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	t.Setenv("TMPDIR", t.TempDir())
+
+	// Without bw_serve_port, the provider runs bw serve itself
+	client, err := bitwarden.NewClient(ctx, bitwarden.ClientConfig{
+		Password: bwtest.Password,
+		Retry:    bitwarden.RetryPolicy{MaxAttempts: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close(ctx)
+
+	item, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Locking stops bw serve, the next call unlocks the vault and starts another one
+	if err := client.Lock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if env.Serve.Unlocked() {
+		t.Error("expected bw serve to be locked")
+	}
+	if _, err := client.GetItem(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if unlocks := env.Serve.Unlocks(); unlocks != 2 {
+		t.Errorf("expected bw serve to be unlocked twice, got %d unlocks", unlocks)
+	}
+	if unlocks := env.Serve.PasswordUnlocks(); unlocks != 0 {
+		t.Errorf("expected the master password to never reach bw serve, got %d unlocks with it", unlocks)
+	}
+}
@@ -0,0 +1,135 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// itemDefaults Where items go when their resource doesn't say, as configured on the provider
+type itemDefaults struct {
+	OrganizationID string
+	CollectionIDs  []string
+	FolderID       string
+}
+
+// itemDefaults Resolves the default_* provider settings
+func (config providerData) itemDefaults(ctx context.Context) (itemDefaults, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var defaults itemDefaults
+
+	for name, setting := range map[string]struct {
+		value  types.String
+		target *string
+	}{
+		"default_organization_id": {config.DefaultOrganizationID, &defaults.OrganizationID},
+		"default_folder_id":       {config.DefaultFolderID, &defaults.FolderID},
+	} {
+		if setting.value.IsUnknown() {
+			diags.AddAttributeError(
+				path.Root(name),
+				"Unable to create client",
+				fmt.Sprintf("Cannot use unknown value as %s", name),
+			)
+			continue
+		}
+		*setting.target = setting.value.ValueString()
+	}
+
+	if config.DefaultCollectionIDs.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("default_collection_ids"),
+			"Unable to create client",
+			"Cannot use unknown value as default_collection_ids",
+		)
+	} else if !config.DefaultCollectionIDs.IsNull() {
+		diags.Append(config.DefaultCollectionIDs.ElementsAs(ctx, &defaults.CollectionIDs, false)...)
+	}
+
+	if len(defaults.CollectionIDs) > 0 && defaults.OrganizationID == "" {
+		diags.AddAttributeError(
+			path.Root("default_collection_ids"),
+			"Invalid defaults",
+			"default_collection_ids requires default_organization_id, collections belong to an organization",
+		)
+	}
+
+	return defaults, diags
+}
+
+// applyItemDefaults Fills in the plan the organization, collections and folder the configuration of an item leaves
+// out with the provider defaults, so that the plan shows where the item is going to be. Without organization nor
+// default organization, or with an empty organization_id, the item goes to the personal vault.
+func (d itemDefaults) applyItemDefaults(ctx context.Context, config tfsdk.Config, plan *tfsdk.Plan) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	organizationPath := path.Root("organization_id")
+	var organizationId types.String
+	diags.Append(config.GetAttribute(ctx, organizationPath, &organizationId)...)
+	if diags.HasError() {
+		return diags
+	}
+	if organizationId.IsNull() {
+		if d.OrganizationID != "" {
+			organizationId = types.StringValue(d.OrganizationID)
+		}
+		diags.Append(plan.SetAttribute(ctx, organizationPath, organizationId)...)
+	}
+	personal := !organizationId.IsUnknown() && organizationId.ValueString() == ""
+
+	collectionsPath := path.Root("collection_ids")
+	var collections types.Set
+	diags.Append(config.GetAttribute(ctx, collectionsPath, &collections)...)
+	if diags.HasError() {
+		return diags
+	}
+	switch {
+	case personal && !collections.IsNull():
+		diags.AddAttributeError(
+			collectionsPath,
+			"Collections of a personal item",
+			"collection_ids requires organization_id, items of the personal vault are not in collections",
+		)
+	case personal:
+		diags.Append(plan.SetAttribute(ctx, collectionsPath, types.SetNull(types.StringType))...)
+	case !collections.IsNull() || organizationId.IsUnknown():
+	case len(d.CollectionIDs) == 0:
+		diags.AddAttributeError(
+			collectionsPath,
+			"Missing collections",
+			"collection_ids must be set, or default_collection_ids in the provider configuration",
+		)
+	case organizationId.ValueString() != d.OrganizationID:
+		diags.AddAttributeError(
+			collectionsPath,
+			"Missing collections",
+			fmt.Sprintf(
+				"collection_ids must be set for items of organization %s, default_collection_ids only applies to "+
+					"the items of default_organization_id",
+				organizationId.ValueString(),
+			),
+		)
+	default:
+		diags.Append(plan.SetAttribute(ctx, collectionsPath, d.CollectionIDs)...)
+	}
+
+	folderPath := path.Root("folder_id")
+	var folderId types.String
+	diags.Append(config.GetAttribute(ctx, folderPath, &folderId)...)
+	if diags.HasError() {
+		return diags
+	}
+	if folderId.IsNull() {
+		folder := types.StringNull()
+		if d.FolderID != "" {
+			folder = types.StringValue(d.FolderID)
+		}
+		diags.Append(plan.SetAttribute(ctx, folderPath, folder)...)
+	}
+
+	return diags
+}
@@ -0,0 +1,230 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// organizationConfirmed Status of the organizations the user is a confirmed member of
+const organizationConfirmed = 2
+
+// vaultDirectory Organizations, collections and folders of the vault, listed once per provider and shared by the
+// resources to check their plans. It is safe for concurrent use.
+type vaultDirectory struct {
+	mu            sync.Mutex
+	organizations map[string]Organization
+	collections   map[string]map[string]Collection
+	folders       map[string]Folder
+}
+
+func newVaultDirectory() *vaultDirectory {
+	return &vaultDirectory{collections: map[string]map[string]Collection{}}
+}
+
+func (d *vaultDirectory) organization(ctx context.Context, vault Vault, id string) (*Organization, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.organizations == nil {
+		organizations, err := vault.ListOrganizations(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		d.organizations = map[string]Organization{}
+		for _, organization := range organizations {
+			d.organizations[organization.ID] = organization
+		}
+	}
+
+	organization, ok := d.organizations[id]
+	if !ok {
+		return nil, nil
+	}
+	return &organization, nil
+}
+
+func (d *vaultDirectory) collection(
+	ctx context.Context,
+	vault Vault,
+	organizationId string,
+	id string,
+) (*Collection, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.collections[organizationId]; !ok {
+		collections, err := vault.ListCollections(ctx, organizationId)
+		if err != nil {
+			return nil, err
+		}
+
+		d.collections[organizationId] = map[string]Collection{}
+		for _, collection := range collections {
+			d.collections[organizationId][collection.ID] = collection
+		}
+	}
+
+	collection, ok := d.collections[organizationId][id]
+	if !ok {
+		return nil, nil
+	}
+	return &collection, nil
+}
+
+func (d *vaultDirectory) folder(ctx context.Context, vault Vault, id string) (*Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.folders == nil {
+		folders, err := vault.ListFolders(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		d.folders = map[string]Folder{}
+		for _, folder := range folders {
+			d.folders[folder.ID] = folder
+		}
+	}
+
+	folder, ok := d.folders[id]
+	if !ok {
+		return nil, nil
+	}
+	return &folder, nil
+}
+
+// vaultDirectory The directory shared by the resources, or a new one when the provider was built without it
+func (p provider) vaultDirectory() *vaultDirectory {
+	if p.directory == nil {
+		return newVaultDirectory()
+	}
+	return p.directory
+}
+
+// validateItemOwnership Checks against the vault that the planned organization exists, that the planned collections
+// belong to it and can be written to, and that the planned folder exists. Unknown values and values unchanged since
+// the prior state are not checked.
+func (p provider) validateItemOwnership(ctx context.Context, plan tfsdk.Plan, state tfsdk.State) diag.Diagnostics {
+	var diags diag.Diagnostics
+	directory := p.vaultDirectory()
+
+	organizationPath := path.Root("organization_id")
+	collectionsPath := path.Root("collection_ids")
+	folderPath := path.Root("folder_id")
+
+	changed := func(path path.Path) (attr.Value, bool) {
+		var planned, prior attr.Value
+		moreDiags := plan.GetAttribute(ctx, path, &planned)
+		diags.Append(moreDiags...)
+		if moreDiags.HasError() {
+			return nil, false
+		}
+		if state.Raw.IsNull() {
+			return planned, true
+		}
+
+		moreDiags = state.GetAttribute(ctx, path, &prior)
+		diags.Append(moreDiags...)
+		return planned, !moreDiags.HasError() && !planned.Equal(prior)
+	}
+
+	lookupError := func(path path.Path, err error) {
+		diags.AddAttributeError(
+			path,
+			"Error validating plan",
+			fmt.Sprintf("bitwarden error when listing the vault\n%s", err),
+		)
+	}
+
+	organizationValue, organizationChanged := changed(organizationPath)
+	collectionsValue, collectionsChanged := changed(collectionsPath)
+	organizationId, _ := organizationValue.(types.String)
+	validOrganization := false
+
+	if (organizationChanged || collectionsChanged) &&
+		!organizationId.IsNull() && !organizationId.IsUnknown() && organizationId.ValueString() != "" {
+		organization, err := directory.organization(ctx, p.client, organizationId.ValueString())
+		switch {
+		case err != nil:
+			lookupError(organizationPath, err)
+		case organization == nil:
+			diags.AddAttributeError(
+				organizationPath,
+				"Organization not found",
+				fmt.Sprintf("organization %s does not exist or you are not a member of it", organizationId.ValueString()),
+			)
+		case !organization.Enabled || organization.Status != organizationConfirmed:
+			diags.AddAttributeError(
+				organizationPath,
+				"Organization not available",
+				fmt.Sprintf(
+					"organization %s (%s) is disabled or your membership is not confirmed yet",
+					organization.Name,
+					organization.ID,
+				),
+			)
+		default:
+			validOrganization = true
+		}
+	}
+
+	collections, _ := collectionsValue.(types.Set)
+	if validOrganization && !collections.IsNull() && !collections.IsUnknown() {
+		for _, elem := range collections.Elements() {
+			collectionId, ok := elem.(types.String)
+			if !ok || collectionId.IsNull() || collectionId.IsUnknown() {
+				continue
+			}
+
+			path := collectionsPath.AtSetValue(collectionId)
+			collection, err := directory.collection(ctx, p.client, organizationId.ValueString(), collectionId.ValueString())
+			switch {
+			case err != nil:
+				lookupError(path, err)
+			case collection == nil:
+				diags.AddAttributeError(
+					path,
+					"Collection not found",
+					fmt.Sprintf(
+						"collection %s does not belong to organization %s, or you do not have access to it",
+						collectionId.ValueString(),
+						organizationId.ValueString(),
+					),
+				)
+			case collection.ReadOnly:
+				diags.AddAttributeError(
+					path,
+					"Read-only collection",
+					fmt.Sprintf("you can read collection %s (%s) but not write to it", collection.Name, collection.ID),
+				)
+			}
+		}
+	}
+
+	folderValue, folderChanged := changed(folderPath)
+	folderId, _ := folderValue.(types.String)
+	if folderChanged && !folderId.IsNull() && !folderId.IsUnknown() {
+		folder, err := directory.folder(ctx, p.client, folderId.ValueString())
+		switch {
+		case err != nil:
+			lookupError(folderPath, err)
+		case folder == nil:
+			diags.AddAttributeError(
+				folderPath,
+				"Folder not found",
+				fmt.Sprintf("folder %s does not exist", folderId.ValueString()),
+			)
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,265 @@
+package bitwarden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/ephemeral/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// convertItemToEphemeral Secrets of an item, as returned by the ephemeral resource. Attributes an item of that type
+// doesn't have are null.
+func convertItemToEphemeral(item *Item) EphemeralItem {
+	result := EphemeralItem{
+		ID:             types.StringValue(item.ID),
+		OrganizationId: types.StringNull(),
+		Type:           types.Int64Value(int64(item.Type)),
+		Name:           types.StringValue(item.Name),
+		Notes:          types.StringNull(),
+		Fields:         []EphemeralItemField{},
+	}
+
+	if item.OrganizationId != "" {
+		result.OrganizationId = types.StringValue(item.OrganizationId)
+	}
+
+	if item.Notes != "" {
+		result.Notes = types.StringValue(item.Notes)
+	}
+
+	if item.Type == loginItemType {
+		result.Login = &EphemeralItemLogin{
+			Username: types.StringValue(item.Login.Username),
+			Password: types.StringValue(item.Login.Password),
+			TOTP:     types.StringValue(item.Login.TOTP),
+			URIs:     []EphemeralItemLoginURI{},
+		}
+		for _, uri := range item.Login.URIs {
+			result.Login.URIs = append(result.Login.URIs, EphemeralItemLoginURI{
+				URI:   types.StringValue(uri.URI),
+				Match: types.Int64Value(int64(uri.Match)),
+			})
+		}
+	}
+
+	for _, field := range item.Fields {
+		result.Fields = append(result.Fields, EphemeralItemField{
+			Name:  types.StringValue(field.Name),
+			Value: types.StringValue(field.Value),
+			Type:  types.Int64Value(int64(field.Type)),
+		})
+	}
+
+	return result
+}
+
+// openEphemeralItems Number of item ephemeral resources open at once. The vault they lock on close is shared with
+// every resource of the provider, only the last one to close locks it.
+type openEphemeralItems struct {
+	mu    sync.Mutex
+	count int
+}
+
+// open Counts an ephemeral resource Terraform is going to close
+func (o *openEphemeralItems) open() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.count++
+}
+
+// close Stops counting an ephemeral resource, returns whether it was the last one open
+func (o *openEphemeralItems) close() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.count = max(o.count-1, 0)
+	return o.count == 0
+}
+
+func newEphemeralItem() ephemeral.EphemeralResource {
+	return &ephemeralItem{}
+}
+
+type ephemeralItem struct {
+	p provider
+}
+
+func (e *ephemeralItem) Metadata(
+	_ context.Context,
+	req ephemeral.MetadataRequest,
+	resp *ephemeral.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_item"
+}
+
+func (e *ephemeralItem) Schema(ctx context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			// ID of the item to read, provided by the user
+			"id": schema.StringAttribute{
+				Required:   true,
+				Validators: []validator.String{uuidValidator{}},
+			},
+			// Org ID of the item, null for items of the personal vault
+			"organization_id": schema.StringAttribute{
+				Computed: true,
+			},
+			// Item type: 1 for logins, 2 for secure notes, 3 for cards, 4 for identities and 5 for SSH keys
+			"type": schema.Int64Attribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Computed: true,
+			},
+			// Notes of the item, the contents of a secure note
+			"notes": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+			// Only set for login items
+			"login": schema.SingleNestedAttribute{
+				Computed: true,
+				Attributes: map[string]schema.Attribute{
+					"username": schema.StringAttribute{
+						Computed: true,
+					},
+					"password": schema.StringAttribute{
+						Computed:  true,
+						Sensitive: true,
+					},
+					// Secret of the authenticator key, usually an otpauth:// URI
+					"totp": schema.StringAttribute{
+						Computed:  true,
+						Sensitive: true,
+					},
+					"uris": schema.ListNestedAttribute{
+						Computed: true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"uri": schema.StringAttribute{
+									Computed: true,
+								},
+								// How BitWarden matches the URI: 0 for domain, 1 for host, 2 for starts with, 3 for
+								// exact, 4 for regular expression and 5 for never
+								"match": schema.Int64Attribute{
+									Computed: true,
+								},
+							},
+						},
+					},
+				},
+			},
+			// Custom fields of the item, in their BitWarden order
+			"fields": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"value": schema.StringAttribute{
+							Computed:  true,
+							Sensitive: true,
+						},
+						// Field type: 0 for text, 1 for hidden, 2 for boolean and 3 for linked
+						"type": schema.Int64Attribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			// Time allowed to read the item, provided by the user, defaults to 10 minutes
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (e *ephemeralItem) Configure(
+	_ context.Context,
+	req ephemeral.ConfigureRequest,
+	_ *ephemeral.ConfigureResponse,
+) {
+	// Left unconfigured until the provider is, Open reports it
+	if p, ok := req.ProviderData.(*provider); ok {
+		e.p = *p
+	}
+}
+
+// Open Unlocks the vault if needed and reads the item, whose secrets Terraform keeps out of the plan and the state
+func (e *ephemeralItem) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	if !e.p.configured {
+		resp.Diagnostics.AddError(providerErrorTitle, providerErrorMessage)
+		return
+	}
+
+	var config EphemeralItem
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withTimeout(ctx, config.Timeouts.Open)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := config.ID.ValueString()
+	item, err := e.p.client.GetItem(ctx, id)
+	if err == nil && item.InTrash() {
+		err = fmt.Errorf("item %s is in the trash", id)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading item",
+			fmt.Sprintf("Could not read item ID %s: %s", id, err.Error()),
+		)
+		return
+	}
+
+	result := convertItemToEphemeral(item)
+	result.Timeouts = config.Timeouts
+	diags = resp.Result.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	e.p.openEphemeralItems.open()
+}
+
+// Close Locks the vault once Terraform is done with the secrets of every open item, it is unlocked again if the run
+// needs it
+func (e *ephemeralItem) Close(ctx context.Context, _ ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	if !e.p.configured || !e.p.openEphemeralItems.close() {
+		return
+	}
+
+	// Close has no configuration to read the timeouts from
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	err := e.p.client.Lock(ctx)
+	if errors.Is(err, ErrCannotLock) {
+		resp.Diagnostics.AddWarning(
+			"Vault left unlocked",
+			fmt.Sprintf("Could not lock the vault: %s. Provide the master password to lock it.", err.Error()),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error locking vault",
+			fmt.Sprintf("Could not lock the vault: %s", err.Error()),
+		)
+	}
+}
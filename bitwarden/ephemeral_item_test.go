@@ -0,0 +1,184 @@
+package bitwarden
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func ephemeralItemSchema(t *testing.T) schema.Schema {
+	t.Helper()
+
+	resp := ephemeral.SchemaResponse{}
+	(&ephemeralItem{}).Schema(context.Background(), ephemeral.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", resp.Diagnostics)
+	}
+	return resp.Schema
+}
+
+// newTestEphemeralItem Item ephemeral resource of a provider configured with the given vault
+func newTestEphemeralItem(vault Vault) *ephemeralItem {
+	return &ephemeralItem{p: provider{configured: true, client: vault, openEphemeralItems: &openEphemeralItems{}}}
+}
+
+func openEphemeralItem(t *testing.T, e *ephemeralItem, id string) ephemeral.OpenResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	s := ephemeralItemSchema(t)
+	// Configurations can't be set, the state of the same schema builds the raw value
+	state := tfsdk.State{Schema: s}
+	if diags := state.Set(ctx, EphemeralItem{ID: types.StringValue(id), Timeouts: nullEphemeralTimeouts(ctx)}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	config := tfsdk.Config{Schema: s, Raw: state.Raw}
+
+	resp := ephemeral.OpenResponse{
+		Result: tfsdk.EphemeralResultData{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)},
+	}
+	e.Open(ctx, ephemeral.OpenRequest{Config: config}, &resp)
+	return resp
+}
+
+func TestEphemeralItemOpenLogin(t *testing.T) {
+	vault := NewFakeVault()
+	org := vault.AddOrganization("Org")
+	item := vault.AddItem(Item{
+		OrganizationId: org.ID,
+		Type:           loginItemType,
+		Name:           "Database",
+		Login: ItemLogin{
+			Username: "admin",
+			Password: "hunter2",
+			URIs:     []ItemLoginURI{{URI: "https://db.example.com", Match: 1}},
+		},
+		Fields: []ItemField{{Name: "port", Value: "5432", Type: 0}},
+	})
+
+	resp := openEphemeralItem(t, newTestEphemeralItem(vault), item.ID)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var result EphemeralItem
+	if diags := resp.Result.Get(context.Background(), &result); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if result.OrganizationId.ValueString() != org.ID || result.Name.ValueString() != "Database" {
+		t.Errorf("unexpected item %+v", result)
+	}
+	if !result.Notes.IsNull() {
+		t.Errorf("expected null notes, got %v", result.Notes)
+	}
+	if result.Login == nil || result.Login.Password.ValueString() != "hunter2" {
+		t.Fatalf("expected the login password, got %+v", result.Login)
+	}
+	if len(result.Login.URIs) != 1 || result.Login.URIs[0].Match.ValueInt64() != 1 {
+		t.Errorf("unexpected URIs %+v", result.Login.URIs)
+	}
+	if len(result.Fields) != 1 || result.Fields[0].Value.ValueString() != "5432" {
+		t.Errorf("unexpected fields %+v", result.Fields)
+	}
+}
+
+func TestEphemeralItemOpenSecureNote(t *testing.T) {
+	vault := NewFakeVault()
+	item := vault.AddItem(Item{Type: secureNoteItemType, Name: "Note", Notes: "secret"})
+
+	resp := openEphemeralItem(t, newTestEphemeralItem(vault), item.ID)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var result EphemeralItem
+	if diags := resp.Result.Get(context.Background(), &result); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if result.Notes.ValueString() != "secret" || !result.OrganizationId.IsNull() || result.Login != nil {
+		t.Errorf("unexpected item %+v", result)
+	}
+}
+
+func TestEphemeralItemOpenErrors(t *testing.T) {
+	vault := NewFakeVault()
+	trashed := vault.AddItem(Item{Type: secureNoteItemType, Name: "Note"})
+	vault.TrashItem(trashed.ID)
+
+	for name, id := range map[string]string{
+		"missing": newUUID(),
+		"trashed": trashed.ID,
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := openEphemeralItem(t, newTestEphemeralItem(vault), id)
+			if !resp.Diagnostics.HasError() {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func closeEphemeralItem(t *testing.T, e *ephemeralItem) ephemeral.CloseResponse {
+	t.Helper()
+
+	resp := ephemeral.CloseResponse{}
+	e.Close(context.Background(), ephemeral.CloseRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+func TestEphemeralItemCloseLocksVault(t *testing.T) {
+	vault := NewFakeVault()
+	item := vault.AddItem(Item{Type: secureNoteItemType, Name: "Note", Notes: "secret"})
+	e := newTestEphemeralItem(vault)
+
+	// Two instances are open at once, the vault is only locked once both are closed
+	for range 2 {
+		if resp := openEphemeralItem(t, e, item.ID); resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+	}
+
+	closeEphemeralItem(t, e)
+	if vault.LockCount() != 0 {
+		t.Fatalf("expected the vault to stay unlocked while an instance is open, got %d locks", vault.LockCount())
+	}
+
+	closeEphemeralItem(t, e)
+	if vault.LockCount() != 1 {
+		t.Errorf("expected the vault to be locked once, got %d", vault.LockCount())
+	}
+}
+
+// sessionKeyVault Vault unlocked with a session key and no master password
+type sessionKeyVault struct {
+	*FakeVault
+}
+
+func (v sessionKeyVault) Lock(_ context.Context) error {
+	return ErrCannotLock
+}
+
+func TestEphemeralItemCloseWarnsWhenVaultCannotBeLocked(t *testing.T) {
+	vault := NewFakeVault()
+	item := vault.AddItem(Item{Type: secureNoteItemType, Name: "Note", Notes: "secret"})
+	e := newTestEphemeralItem(sessionKeyVault{vault})
+
+	if resp := openEphemeralItem(t, e, item.ID); resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	resp := closeEphemeralItem(t, e)
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the vault left unlocked, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,283 @@
+package bitwarden
+
+import (
+	"context"
+	"encoding/base32"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/samber/lo"
+)
+
+// Defaults of the otpauth parameters, as authenticators and BitWarden apply them
+const (
+	defaultOTPDigits    = 6
+	defaultOTPPeriod    = 30
+	defaultOTPAlgorithm = "SHA1"
+)
+
+// otpAlgorithms Hash algorithms BitWarden computes TOTP codes with
+var otpAlgorithms = []string{"SHA1", "SHA256", "SHA512"}
+
+// OTPAuth Authenticator key of a login, as written in an otpauth://totp/ URI
+type OTPAuth struct {
+	Secret    string `tfsdk:"secret"`
+	Issuer    string `tfsdk:"issuer"`
+	Account   string `tfsdk:"account"`
+	Digits    int64  `tfsdk:"digits"`
+	Period    int64  `tfsdk:"period"`
+	Algorithm string `tfsdk:"algorithm"`
+}
+
+var otpAuthAttributeTypes = map[string]attr.Type{
+	"secret":    types.StringType,
+	"issuer":    types.StringType,
+	"account":   types.StringType,
+	"digits":    types.Int64Type,
+	"period":    types.Int64Type,
+	"algorithm": types.StringType,
+}
+
+// normalizeOTPSecret Upper cases the base32 secret and removes the spaces and padding authenticators don't mind
+func normalizeOTPSecret(secret string) (string, error) {
+	secret = strings.TrimRight(strings.ToUpper(strings.ReplaceAll(secret, " ", "")), "=")
+	if secret == "" {
+		return "", fmt.Errorf("the secret is empty")
+	}
+
+	_, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return "", fmt.Errorf("the secret is not base32 encoded: %s", err)
+	}
+
+	return secret, nil
+}
+
+// validate Normalizes the secret and the algorithm, and checks the key can be used by BitWarden
+func (o OTPAuth) validate() (OTPAuth, error) {
+	var err error
+	o.Secret, err = normalizeOTPSecret(o.Secret)
+	if err != nil {
+		return o, err
+	}
+
+	o.Algorithm = strings.ToUpper(o.Algorithm)
+	if !lo.Contains[string](otpAlgorithms, o.Algorithm) {
+		return o, fmt.Errorf("unsupported algorithm %q, expected one of %s", o.Algorithm, strings.Join(otpAlgorithms, ", "))
+	}
+
+	if o.Digits < 1 || o.Digits > 10 {
+		return o, fmt.Errorf("digits must be between 1 and 10, got %d", o.Digits)
+	}
+
+	if o.Period < 1 {
+		return o, fmt.Errorf("period must be positive, got %d", o.Period)
+	}
+
+	if strings.Contains(o.Issuer, ":") {
+		return o, fmt.Errorf("the issuer can't contain a colon")
+	}
+
+	if o.Issuer == "" && strings.Contains(o.Account, ":") {
+		return o, fmt.Errorf("the account can't contain a colon without issuer")
+	}
+
+	return o, nil
+}
+
+// parseOTPAuth Reads an otpauth://totp/ URI, parameters it doesn't set get their default
+func parseOTPAuth(uri string) (OTPAuth, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return OTPAuth{}, err
+	}
+
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" {
+		return OTPAuth{}, fmt.Errorf("expected an otpauth://totp/ URI")
+	}
+
+	query := parsed.Query()
+	result := OTPAuth{
+		Secret:    query.Get("secret"),
+		Issuer:    query.Get("issuer"),
+		Account:   strings.TrimPrefix(parsed.Path, "/"),
+		Digits:    defaultOTPDigits,
+		Period:    defaultOTPPeriod,
+		Algorithm: defaultOTPAlgorithm,
+	}
+
+	// The label is "issuer:account" or "account", the issuer parameter wins over the one of the label
+	if issuer, account, found := strings.Cut(result.Account, ":"); found {
+		result.Account = strings.TrimLeft(account, " ")
+		if result.Issuer == "" {
+			result.Issuer = issuer
+		}
+	}
+
+	for name, value := range map[string]*int64{"digits": &result.Digits, "period": &result.Period} {
+		if !query.Has(name) {
+			continue
+		}
+
+		*value, err = strconv.ParseInt(query.Get(name), 10, 64)
+		if err != nil {
+			return OTPAuth{}, fmt.Errorf("%s is not an integer: %q", name, query.Get(name))
+		}
+	}
+
+	if query.Has("algorithm") {
+		result.Algorithm = query.Get("algorithm")
+	}
+
+	return result.validate()
+}
+
+// buildOTPAuth Writes an otpauth://totp/ URI, leaving out the parameters set to their default
+func buildOTPAuth(o OTPAuth) (string, error) {
+	o, err := o.validate()
+	if err != nil {
+		return "", err
+	}
+
+	label := url.PathEscape(o.Account)
+	params := []string{"secret=" + o.Secret}
+	if o.Issuer != "" {
+		label = url.PathEscape(o.Issuer) + ":" + label
+		// Spaces are written %20 rather than +, which some authenticators would keep
+		params = append(params, "issuer="+strings.ReplaceAll(url.QueryEscape(o.Issuer), "+", "%20"))
+	}
+	if o.Algorithm != defaultOTPAlgorithm {
+		params = append(params, "algorithm="+o.Algorithm)
+	}
+	if o.Digits != defaultOTPDigits {
+		params = append(params, fmt.Sprintf("digits=%d", o.Digits))
+	}
+	if o.Period != defaultOTPPeriod {
+		params = append(params, fmt.Sprintf("period=%d", o.Period))
+	}
+
+	return "otpauth://totp/" + label + "?" + strings.Join(params, "&"), nil
+}
+
+func newFunctionParseOTPAuth() function.Function {
+	return &functionParseOTPAuth{}
+}
+
+type functionParseOTPAuth struct{}
+
+func (f *functionParseOTPAuth) Metadata(
+	_ context.Context,
+	_ function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "parse_otpauth"
+}
+
+func (f *functionParseOTPAuth) Definition(
+	_ context.Context,
+	_ function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Parse an otpauth URI",
+		Description: "Reads the secret, issuer, account, digits, period and algorithm of an otpauth://totp/ URI. " +
+			"Missing parameters get their default: 6 digits, a period of 30 seconds and the SHA1 algorithm.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "uri",
+				Description: "otpauth://totp/ URI, like the authenticator key of a BitWarden login.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: otpAuthAttributeTypes},
+	}
+}
+
+func (f *functionParseOTPAuth) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var uri string
+	resp.Error = req.Arguments.Get(ctx, &uri)
+	if resp.Error != nil {
+		return
+	}
+
+	result, err := parseOTPAuth(uri)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid otpauth URI: %s", err))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+func newFunctionBuildOTPAuth() function.Function {
+	return &functionBuildOTPAuth{}
+}
+
+type functionBuildOTPAuth struct{}
+
+func (f *functionBuildOTPAuth) Metadata(
+	_ context.Context,
+	_ function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "build_otpauth"
+}
+
+func (f *functionBuildOTPAuth) Definition(
+	_ context.Context,
+	_ function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Build an otpauth URI",
+		Description: "Writes the otpauth://totp/ URI of an authenticator key, the reverse of parse_otpauth. " +
+			"Parameters set to their default are left out of the URI.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "secret",
+				Description: "Base32 encoded secret, spaces and padding are removed.",
+			},
+			function.StringParameter{
+				Name:        "issuer",
+				Description: "Service the key is for, an empty string for none.",
+			},
+			function.StringParameter{
+				Name:        "account",
+				Description: "Account the key is for, usually a username or an email.",
+			},
+			function.Int64Parameter{
+				Name:        "digits",
+				Description: "Number of digits of the codes, between 1 and 10, usually 6.",
+			},
+			function.Int64Parameter{
+				Name:        "period",
+				Description: "Seconds during which a code is valid, usually 30.",
+			},
+			function.StringParameter{
+				Name:        "algorithm",
+				Description: "SHA1, SHA256 or SHA512, usually SHA1.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *functionBuildOTPAuth) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var o OTPAuth
+	resp.Error = req.Arguments.Get(ctx, &o.Secret, &o.Issuer, &o.Account, &o.Digits, &o.Period, &o.Algorithm)
+	if resp.Error != nil {
+		return
+	}
+
+	uri, err := buildOTPAuth(o)
+	if err != nil {
+		resp.Error = function.NewFuncError(fmt.Sprintf("Invalid authenticator key: %s", err))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, uri)
+}
@@ -0,0 +1,163 @@
+package bitwarden
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseOTPAuth(t *testing.T) {
+	for uri, expected := range map[string]OTPAuth{
+		"otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&issuer=GitHub": {
+			Secret: "JBSWY3DPEHPK3PXP", Issuer: "GitHub", Account: "octocat",
+			Digits: 6, Period: 30, Algorithm: "SHA1",
+		},
+		"otpauth://totp/ACME%20Co:john@example.com?secret=jbsw%20y3dp%20ehpk%203pxp&algorithm=sha256&digits=8&period=60": {
+			Secret: "JBSWY3DPEHPK3PXP", Issuer: "ACME Co", Account: "john@example.com",
+			Digits: 8, Period: 60, Algorithm: "SHA256",
+		},
+		"otpauth://totp/john?secret=JBSWY3DPEHPK3PXP": {
+			Secret: "JBSWY3DPEHPK3PXP", Account: "john",
+			Digits: 6, Period: 30, Algorithm: "SHA1",
+		},
+		// The issuer parameter wins over the label
+		"otpauth://totp/Old:%20john?secret=JBSWY3DPEHPK3PXP&issuer=New": {
+			Secret: "JBSWY3DPEHPK3PXP", Issuer: "New", Account: "john",
+			Digits: 6, Period: 30, Algorithm: "SHA1",
+		},
+	} {
+		parsed, err := parseOTPAuth(uri)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", uri, err)
+			continue
+		}
+		if parsed != expected {
+			t.Errorf("%s: expected %+v, got %+v", uri, expected, parsed)
+		}
+	}
+}
+
+func TestParseOTPAuthErrors(t *testing.T) {
+	for _, uri := range []string{
+		"https://example.com",
+		"otpauth://hotp/john?secret=JBSWY3DPEHPK3PXP&counter=1",
+		"otpauth://totp/john",
+		"otpauth://totp/john?secret=not-base32!",
+		"otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&digits=eight",
+		"otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&digits=12",
+		"otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&period=0",
+		"otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+	} {
+		if _, err := parseOTPAuth(uri); err == nil {
+			t.Errorf("%s: expected an error", uri)
+		}
+	}
+}
+
+func TestBuildOTPAuth(t *testing.T) {
+	for expected, o := range map[string]OTPAuth{
+		"otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&issuer=GitHub": {
+			Secret: "JBSWY3DPEHPK3PXP", Issuer: "GitHub", Account: "octocat",
+			Digits: 6, Period: 30, Algorithm: "SHA1",
+		},
+		"otpauth://totp/ACME%20Co:john@example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co&algorithm=SHA512&digits=8&period=60": {
+			Secret: "jbsw y3dp ehpk 3pxp====", Issuer: "ACME Co", Account: "john@example.com",
+			Digits: 8, Period: 60, Algorithm: "sha512",
+		},
+		"otpauth://totp/john?secret=JBSWY3DPEHPK3PXP": {
+			Secret: "JBSWY3DPEHPK3PXP", Account: "john",
+			Digits: 6, Period: 30, Algorithm: "SHA1",
+		},
+	} {
+		uri, err := buildOTPAuth(o)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", expected, err)
+			continue
+		}
+		if uri != expected {
+			t.Errorf("expected %s, got %s", expected, uri)
+		}
+
+		// Building then parsing gives the normalized key back
+		normalized, _ := o.validate()
+		if parsed, err := parseOTPAuth(uri); err != nil || parsed != normalized {
+			t.Errorf("%s: expected to parse back %+v, got %+v (%v)", uri, normalized, parsed, err)
+		}
+	}
+}
+
+func TestBuildOTPAuthErrors(t *testing.T) {
+	valid := OTPAuth{Secret: "JBSWY3DPEHPK3PXP", Issuer: "GitHub", Account: "octocat", Digits: 6, Period: 30, Algorithm: "SHA1"}
+	for name, modify := range map[string]func(o *OTPAuth){
+		"empty secret":      func(o *OTPAuth) { o.Secret = "" },
+		"colon in issuer":   func(o *OTPAuth) { o.Issuer = "Git:Hub" },
+		"ambiguous label":   func(o *OTPAuth) { o.Issuer, o.Account = "", "Git:Hub" },
+		"no digits":         func(o *OTPAuth) { o.Digits = 0 },
+		"negative period":   func(o *OTPAuth) { o.Period = -30 },
+		"unknown algorithm": func(o *OTPAuth) { o.Algorithm = "" },
+	} {
+		o := valid
+		modify(&o)
+		if _, err := buildOTPAuth(o); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFunctionParseOTPAuth(t *testing.T) {
+	ctx := context.Background()
+	resp := function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(otpAuthAttributeTypes))}
+	(&functionParseOTPAuth{}).Run(ctx, function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{
+			types.StringValue("otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&period=60"),
+		}),
+	}, &resp)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+
+	expected := types.ObjectValueMust(otpAuthAttributeTypes, map[string]attr.Value{
+		"secret":    types.StringValue("JBSWY3DPEHPK3PXP"),
+		"issuer":    types.StringValue("GitHub"),
+		"account":   types.StringValue("octocat"),
+		"digits":    types.Int64Value(6),
+		"period":    types.Int64Value(60),
+		"algorithm": types.StringValue("SHA1"),
+	})
+	if !resp.Result.Value().Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, resp.Result.Value())
+	}
+
+	resp = function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(otpAuthAttributeTypes))}
+	(&functionParseOTPAuth{}).Run(ctx, function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("otpauth://totp/octocat")}),
+	}, &resp)
+	if resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0 {
+		t.Errorf("expected an error on the URI, got %v", resp.Error)
+	}
+}
+
+func TestFunctionBuildOTPAuth(t *testing.T) {
+	resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	(&functionBuildOTPAuth{}).Run(context.Background(), function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{
+			types.StringValue("JBSWY3DPEHPK3PXP"),
+			types.StringValue("GitHub"),
+			types.StringValue("octocat"),
+			types.Int64Value(6),
+			types.Int64Value(30),
+			types.StringValue("SHA256"),
+		}),
+	}, &resp)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+
+	expected := "otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&issuer=GitHub&algorithm=SHA256"
+	if !resp.Result.Value().Equal(types.StringValue(expected)) {
+		t.Errorf("expected %s, got %s", expected, resp.Result.Value())
+	}
+}
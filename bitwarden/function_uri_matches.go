@@ -0,0 +1,178 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"golang.org/x/net/publicsuffix"
+)
+
+// uriMatchTypes Match detections of the URIs of a login, by their name and by the number BitWarden stores
+var uriMatchTypes = []string{"domain", "host", "starts_with", "exact", "regex", "never"}
+
+// parseURIMatchType Reads a match detection given by name, or by number like in the login URIs. Without one,
+// BitWarden matches on the domain.
+func parseURIMatchType(matchType *string) (string, error) {
+	if matchType == nil {
+		return "domain", nil
+	}
+
+	if index, err := strconv.Atoi(*matchType); err == nil && index >= 0 && index < len(uriMatchTypes) {
+		return uriMatchTypes[index], nil
+	}
+
+	for _, name := range uriMatchTypes {
+		if name == *matchType {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown match type %q, expected one of %s", *matchType, strings.Join(uriMatchTypes, ", "))
+}
+
+// parseURIHost Parses a URI the way BitWarden does, assuming http:// when it has no scheme
+func parseURIHost(uri string) *url.URL {
+	if !strings.Contains(uri, "://") {
+		uri = "http://" + uri
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Hostname() == "" {
+		return nil
+	}
+
+	return parsed
+}
+
+// uriHost Host and port of a URI, the default port of its scheme left out. Empty when it has none.
+func uriHost(uri string) string {
+	parsed := parseURIHost(uri)
+	if parsed == nil {
+		return ""
+	}
+
+	host := strings.ToLower(parsed.Host)
+	if port := parsed.Port(); (parsed.Scheme == "http" && port == "80") || (parsed.Scheme == "https" && port == "443") {
+		host = strings.TrimSuffix(host, ":"+port)
+	}
+
+	return host
+}
+
+// uriDomain Registrable domain of a URI, like example.co.uk for www.example.co.uk, or its host name for IP addresses
+// and localhost. Empty when it has none.
+func uriDomain(uri string) string {
+	parsed := parseURIHost(uri)
+	if parsed == nil {
+		return ""
+	}
+
+	hostname := strings.ToLower(parsed.Hostname())
+	if hostname == "localhost" || net.ParseIP(hostname) != nil {
+		return hostname
+	}
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(hostname)
+	if err != nil {
+		return ""
+	}
+
+	return domain
+}
+
+// uriMatches Whether BitWarden would offer a login with the URI pattern on the page at uri
+func uriMatches(uri string, pattern string, matchType string) (bool, error) {
+	switch matchType {
+	case "domain":
+		domain := uriDomain(pattern)
+		return domain != "" && domain == uriDomain(uri), nil
+	case "host":
+		host := uriHost(pattern)
+		return host != "" && host == uriHost(uri), nil
+	case "starts_with":
+		return strings.HasPrefix(uri, pattern), nil
+	case "exact":
+		return uri == pattern, nil
+	case "regex":
+		// Like the JavaScript regular expressions of BitWarden, not anchored and case-insensitive
+		expression, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid regular expression: %s", err)
+		}
+		return expression.MatchString(uri), nil
+	default:
+		return false, nil
+	}
+}
+
+func newFunctionURIMatches() function.Function {
+	return &functionURIMatches{}
+}
+
+type functionURIMatches struct{}
+
+func (f *functionURIMatches) Metadata(
+	_ context.Context,
+	_ function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "uri_matches"
+}
+
+func (f *functionURIMatches) Definition(
+	_ context.Context,
+	_ function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Check whether a URI matches a login URI",
+		Description: "Tells whether BitWarden would offer a login on the page at uri, given one of its URIs and the " +
+			"match detection of that URI.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "uri",
+				Description: "URI of the page.",
+			},
+			function.StringParameter{
+				Name:        "pattern",
+				Description: "URI of the login.",
+			},
+			function.StringParameter{
+				Name:           "match_type",
+				AllowNullValue: true,
+				Description: "domain, host, starts_with, exact, regex or never, or the number of the match " +
+					"detection as stored in the login (0 to 5). Null matches on the domain, like BitWarden.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *functionURIMatches) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var uri, pattern string
+	var matchType *string
+	resp.Error = req.Arguments.Get(ctx, &uri, &pattern, &matchType)
+	if resp.Error != nil {
+		return
+	}
+
+	match, err := parseURIMatchType(matchType)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(2, err.Error())
+		return
+	}
+
+	matches, err := uriMatches(uri, pattern, match)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, matches)
+}
@@ -0,0 +1,101 @@
+package bitwarden
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestURIMatches(t *testing.T) {
+	for _, test := range []struct {
+		uri       string
+		pattern   string
+		matchType string
+		expected  bool
+	}{
+		{"https://accounts.google.com/login", "google.com", "domain", true},
+		{"https://www.example.co.uk/", "https://login.example.co.uk", "domain", true},
+		{"https://example.co.uk/", "https://other.co.uk", "domain", false},
+		{"https://myapp.herokuapp.com", "https://otherapp.herokuapp.com", "domain", false},
+		{"http://192.168.1.1:8080/admin", "192.168.1.1", "domain", true},
+		{"http://localhost:3000", "http://localhost", "domain", true},
+		{"https://example.com", "not a uri", "domain", false},
+		{"https://login.example.com/a", "https://LOGIN.example.com/b", "host", true},
+		{"https://example.com/", "https://login.example.com", "host", false},
+		{"https://example.com:8443/", "https://example.com", "host", false},
+		{"https://example.com:443/", "https://example.com", "host", true},
+		{"https://example.com/admin/users", "https://example.com/admin", "starts_with", true},
+		{"https://example.com/public", "https://example.com/admin", "starts_with", false},
+		{"https://example.com/admin", "https://example.com/admin", "exact", true},
+		{"https://example.com/admin/", "https://example.com/admin", "exact", false},
+		{"https://EXAMPLE.com/app/42", `^https://example\.com/app/\d+$`, "regex", true},
+		{"https://example.com/app/x", `^https://example\.com/app/\d+$`, "regex", false},
+		{"https://example.com", "https://example.com", "never", false},
+	} {
+		matches, err := uriMatches(test.uri, test.pattern, test.matchType)
+		if err != nil {
+			t.Errorf("%s %s %s: unexpected error: %s", test.uri, test.matchType, test.pattern, err)
+			continue
+		}
+		if matches != test.expected {
+			t.Errorf("%s %s %s: expected %t, got %t", test.uri, test.matchType, test.pattern, test.expected, matches)
+		}
+	}
+
+	if _, err := uriMatches("https://example.com", "(", "regex"); err == nil {
+		t.Error("expected an invalid regular expression to fail")
+	}
+}
+
+func TestParseURIMatchType(t *testing.T) {
+	host, four := "host", "4"
+	for expected, matchType := range map[string]*string{"domain": nil, "host": &host, "regex": &four} {
+		parsed, err := parseURIMatchType(matchType)
+		if err != nil || parsed != expected {
+			t.Errorf("expected %s, got %s (%v)", expected, parsed, err)
+		}
+	}
+
+	for _, matchType := range []string{"starts-with", "6", "-1"} {
+		if _, err := parseURIMatchType(&matchType); err == nil {
+			t.Errorf("%s: expected an error", matchType)
+		}
+	}
+}
+
+func TestFunctionURIMatches(t *testing.T) {
+	run := func(matchType attr.Value) function.RunResponse {
+		resp := function.RunResponse{Result: function.NewResultData(types.BoolUnknown())}
+		(&functionURIMatches{}).Run(context.Background(), function.RunRequest{
+			Arguments: function.NewArgumentsData([]attr.Value{
+				types.StringValue("https://vault.example.com/#/login"),
+				types.StringValue("https://example.com"),
+				matchType,
+			}),
+		}, &resp)
+		return resp
+	}
+
+	for matchType, expected := range map[attr.Value]bool{
+		types.StringNull():         true,
+		types.StringValue("1"):     false,
+		types.StringValue("exact"): false,
+	} {
+		resp := run(matchType)
+		if resp.Error != nil {
+			t.Errorf("%s: unexpected error: %s", matchType, resp.Error)
+			continue
+		}
+		if !resp.Result.Value().Equal(types.BoolValue(expected)) {
+			t.Errorf("%s: expected %t, got %s", matchType, expected, resp.Result.Value())
+		}
+	}
+
+	resp := run(types.StringValue("fuzzy"))
+	if resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 2 {
+		t.Errorf("expected an error on the match type, got %v", resp.Error)
+	}
+}
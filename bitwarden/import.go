@@ -0,0 +1,90 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// importID Identifies the item to import, either by ID or by organization, collection and name
+type importID struct {
+	ID             string
+	OrganizationId string
+	CollectionId   string
+	Name           string
+}
+
+// parseImportID Parses "<id>" or "<organization_id>/<collection_id>/<name>", the name may contain slashes.
+// Personal items are imported by name with an empty organization and collection, as in "//<name>".
+func parseImportID(id string) (importID, error) {
+	parts := strings.SplitN(id, "/", 3)
+
+	switch {
+	case len(parts) == 1 && id != "":
+		return importID{ID: id}, nil
+	case len(parts) != 3 || parts[2] == "":
+		return importID{}, fmt.Errorf(
+			"expected an item ID or <organization_id>/<collection_id>/<name>, got %q",
+			id,
+		)
+	case (parts[0] == "") != (parts[1] == ""):
+		return importID{}, fmt.Errorf(
+			"the organization and the collection go together in %q, personal items are imported with //<name>",
+			id,
+		)
+	}
+
+	return importID{OrganizationId: parts[0], CollectionId: parts[1], Name: parts[2]}, nil
+}
+
+// findItem Looks up the item an import ID refers to, names have to match exactly and only one item of the given type
+func findItem(ctx context.Context, vault Vault, id importID, itemType int) (*Item, error) {
+	if id.ID != "" {
+		item, err := vault.GetItem(ctx, id.ID)
+		if err != nil {
+			return nil, err
+		}
+		if item.InTrash() {
+			return nil, fmt.Errorf("item %s is in the trash", id.ID)
+		}
+		if item.Type != itemType {
+			return nil, fmt.Errorf("item %s is of type %d, expected %d", id.ID, item.Type, itemType)
+		}
+		return item, nil
+	}
+
+	items, err := vault.ListItems(ctx, id.OrganizationId, id.CollectionId, id.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// bw searches names by substring, in every organization when none is given
+	matches := lo.Filter[Item](items, func(item Item, _ int) bool {
+		return item.Type == itemType && item.Name == id.Name && item.OrganizationId == id.OrganizationId
+	})
+
+	owner := "personal vault"
+	if id.OrganizationId != "" {
+		owner = fmt.Sprintf("collection %s of organization %s", id.CollectionId, id.OrganizationId)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: no item named %q in the %s", ErrItemNotFound, id.Name, owner)
+	case 1:
+		return &matches[0], nil
+	}
+
+	ids := lo.Map[Item, string](matches, func(item Item, _ int) string { return item.ID })
+	sort.Strings(ids)
+	return nil, fmt.Errorf(
+		"%d items are named %q in the %s, import one of them by ID instead: %s",
+		len(matches),
+		id.Name,
+		owner,
+		strings.Join(ids, ", "),
+	)
+}
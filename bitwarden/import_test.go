@@ -0,0 +1,31 @@
+package bitwarden
+
+import "testing"
+
+func TestParseImportID(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected importID
+		valid    bool
+	}{
+		{id: "item-id", expected: importID{ID: "item-id"}, valid: true},
+		{id: "org/collection/Note", expected: importID{OrganizationId: "org", CollectionId: "collection", Name: "Note"}, valid: true},
+		{id: "org/collection/a/b", expected: importID{OrganizationId: "org", CollectionId: "collection", Name: "a/b"}, valid: true},
+		{id: "//Note", expected: importID{Name: "Note"}, valid: true},
+		{id: ""},
+		{id: "org/Note"},
+		{id: "org/collection/"},
+		{id: "org//Note"},
+		{id: "/collection/Note"},
+	}
+
+	for _, test := range tests {
+		parsed, err := parseImportID(test.id)
+		if test.valid && (err != nil || parsed != test.expected) {
+			t.Errorf("expected %q to parse to %#v, got %#v (%v)", test.id, test.expected, parsed, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("expected %q to be rejected, got %#v", test.id, parsed)
+		}
+	}
+}
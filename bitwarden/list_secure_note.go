@@ -0,0 +1,170 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/list/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/samber/lo"
+)
+
+// ItemFilter Represents the configuration of the list resources, every filter left null matches all items
+type ItemFilter struct {
+	OrganizationId types.String `tfsdk:"organization_id"`
+	CollectionId   types.String `tfsdk:"collection_id"`
+	FolderId       types.String `tfsdk:"folder_id"`
+	Search         types.String `tfsdk:"search"`
+	// Timeouts Time allowed to list the items, defaults to 10 minutes
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func itemFilterSchema(ctx context.Context, items string) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Only lists the %s of this organization, or of the personal vault with \"\".", items),
+				Validators:  []validator.String{uuidValidator{allowEmpty: true}},
+			},
+			"collection_id": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Only lists the %s of this collection.", items),
+				Validators:  []validator.String{uuidValidator{}},
+			},
+			"folder_id": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Only lists the %s of this folder.", items),
+				Validators:  []validator.String{uuidValidator{}},
+			},
+			"search": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Only lists the %s whose name contains this string, ignoring case.", items),
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+// filterItems Lists the items of a type matching the filter, sorted by name. Items in the trash are left out.
+func filterItems(ctx context.Context, vault Vault, filter ItemFilter, itemType int) ([]Item, error) {
+	// bw lists every item without organization, the personal vault is filtered below
+	items, err := vault.ListItems(
+		ctx,
+		filter.OrganizationId.ValueString(),
+		filter.CollectionId.ValueString(),
+		filter.Search.ValueString(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	items = lo.Filter[Item](items, func(item Item, _ int) bool {
+		return item.Type == itemType && !item.InTrash() &&
+			(filter.OrganizationId.IsNull() || item.OrganizationId == filter.OrganizationId.ValueString()) &&
+			(filter.FolderId.IsNull() || item.FolderID == filter.FolderId.ValueString())
+	})
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].ID < items[j].ID
+	})
+
+	return items, nil
+}
+
+func newListSecureNote() list.ListResource {
+	return &listSecureNote{}
+}
+
+type listSecureNote struct {
+	p provider
+}
+
+func (l *listSecureNote) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_secure_note"
+}
+
+func (l *listSecureNote) ListResourceConfigSchema(
+	ctx context.Context,
+	_ list.ListResourceSchemaRequest,
+	resp *list.ListResourceSchemaResponse,
+) {
+	resp.Schema = itemFilterSchema(ctx, "secure notes")
+}
+
+func (l *listSecureNote) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	_ *resource.ConfigureResponse,
+) {
+	if p, ok := req.ProviderData.(*provider); ok {
+		l.p = *p
+	}
+}
+
+// List Streams the secure notes matching the filter with their identity, and their state when Terraform generates
+// their configuration
+func (l *listSecureNote) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	if !l.p.configured {
+		var diags diag.Diagnostics
+		diags.AddError(providerErrorTitle, providerErrorMessage)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var filter ItemFilter
+	diags := req.Config.Get(ctx, &filter)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	// Listed before returning, the results are streamed once the timeout is released
+	timeoutCtx, cancel, timeoutDiags := withTimeout(ctx, filter.Timeouts.List)
+	defer cancel()
+	diags.Append(timeoutDiags...)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	secureNotes, err := filterItems(timeoutCtx, l.p.client, filter, secureNoteItemType)
+	if err != nil {
+		diags.AddError("Error listing secure notes", fmt.Sprintf("Could not list secure notes: %s", err.Error()))
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	if req.Limit > 0 && int64(len(secureNotes)) > req.Limit {
+		secureNotes = secureNotes[:req.Limit]
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i := range secureNotes {
+			result := req.NewListResult(ctx)
+			result.DisplayName = secureNotes[i].Name
+
+			result.Diagnostics.Append(setSecureNoteIdentity(ctx, result.Identity, secureNotes[i].ID)...)
+			if req.IncludeResource && !result.Diagnostics.HasError() {
+				result.Diagnostics.Append(result.Resource.Set(ctx, importedSecureNote(ctx, &secureNotes[i]))...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
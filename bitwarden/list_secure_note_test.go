@@ -0,0 +1,208 @@
+package bitwarden
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// identitySchema Identity schema of the secure notes
+func (f *secureNoteFixture) identitySchema(t *testing.T) resource.IdentitySchemaResponse {
+	t.Helper()
+
+	resp := resource.IdentitySchemaResponse{}
+	f.resource.IdentitySchema(context.Background(), resource.IdentitySchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+// list Runs the secure note list resource with the given filter and collects its results
+func (f *secureNoteFixture) list(t *testing.T, filter ItemFilter, includeResource bool) []list.ListResult {
+	t.Helper()
+
+	ctx := context.Background()
+	schemaResp := list.ListResourceSchemaResponse{}
+	(&listSecureNote{}).ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &schemaResp)
+
+	if filter.Timeouts.IsNull() {
+		filter.Timeouts = nullListTimeouts(ctx)
+	}
+
+	// Configurations can't be set, the state of the same schema builds the raw value
+	config := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := config.Set(ctx, filter); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	l := listSecureNote{p: f.resource.p}
+	stream := list.ListResultsStream{}
+	l.List(ctx, list.ListRequest{
+		Config:                 tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw},
+		IncludeResource:        includeResource,
+		ResourceSchema:         f.schema,
+		ResourceIdentitySchema: f.identitySchema(t).IdentitySchema,
+	}, &stream)
+
+	var results []list.ListResult
+	for result := range stream.Results {
+		results = append(results, result)
+	}
+	return results
+}
+
+func listedIDs(t *testing.T, results []list.ListResult) []string {
+	t.Helper()
+
+	var ids []string
+	for _, result := range results {
+		if result.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", result.Diagnostics)
+		}
+
+		var identity SecureNoteIdentity
+		if diags := result.Identity.Get(context.Background(), &identity); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		ids = append(ids, identity.ID.ValueString())
+	}
+	return ids
+}
+
+func TestListSecureNotesFilters(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	other := f.vault.AddCollection(f.org.ID, "Other")
+	folder := f.vault.AddFolder("Folder")
+
+	second := f.vault.AddItem(Item{Type: secureNoteItemType, Name: "B note", OrganizationId: f.org.ID, CollectionIDs: []string{f.collection.ID}})
+	first := f.vault.AddItem(Item{Type: secureNoteItemType, Name: "A note", OrganizationId: f.org.ID, CollectionIDs: []string{f.collection.ID}})
+	elsewhere := f.vault.AddItem(Item{Type: secureNoteItemType, Name: "C note", OrganizationId: f.org.ID, CollectionIDs: []string{other.ID}, FolderID: folder.ID})
+	personal := f.vault.AddItem(Item{Type: secureNoteItemType, Name: "Personal"})
+	f.vault.AddItem(Item{Type: loginItemType, Name: "A login", OrganizationId: f.org.ID, CollectionIDs: []string{f.collection.ID}})
+	trashed := f.vault.AddItem(Item{Type: secureNoteItemType, Name: "Trashed", OrganizationId: f.org.ID, CollectionIDs: []string{f.collection.ID}})
+	f.vault.TrashItem(trashed.ID)
+
+	for name, test := range map[string]struct {
+		filter   ItemFilter
+		expected []string
+	}{
+		"everything": {
+			filter:   ItemFilter{},
+			expected: []string{first.ID, second.ID, elsewhere.ID, personal.ID},
+		},
+		"organization": {
+			filter:   ItemFilter{OrganizationId: types.StringValue(f.org.ID)},
+			expected: []string{first.ID, second.ID, elsewhere.ID},
+		},
+		"personal vault": {
+			filter:   ItemFilter{OrganizationId: types.StringValue("")},
+			expected: []string{personal.ID},
+		},
+		"collection": {
+			filter:   ItemFilter{OrganizationId: types.StringValue(f.org.ID), CollectionId: types.StringValue(f.collection.ID)},
+			expected: []string{first.ID, second.ID},
+		},
+		"folder": {
+			filter:   ItemFilter{FolderId: types.StringValue(folder.ID)},
+			expected: []string{elsewhere.ID},
+		},
+		"search": {
+			filter:   ItemFilter{Search: types.StringValue("b NOTE")},
+			expected: []string{second.ID},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ids := listedIDs(t, f.list(t, test.filter, false))
+			if !sameElements(ids, test.expected) || (len(ids) > 0 && ids[0] != test.expected[0]) {
+				t.Errorf("expected %v sorted by name, got %v", test.expected, ids)
+			}
+		})
+	}
+}
+
+func TestListSecureNotesIncludesResource(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	state := f.create(t, f.plannedNote("Note", "secret"))
+
+	results := f.list(t, ItemFilter{CollectionId: types.StringValue(f.collection.ID)}, true)
+	if len(results) != 1 {
+		t.Fatalf("expected one secure note, got %d", len(results))
+	}
+	if results[0].DisplayName != "Note" {
+		t.Errorf("expected the name of the secure note as display name, got %q", results[0].DisplayName)
+	}
+
+	// Same state as an import, so that the generated configuration plans no change
+	if !results[0].Resource.Raw.Equal(state.Raw) {
+		t.Errorf("expected the listed state to be\n%v\ngot\n%v", state.Raw, results[0].Resource.Raw)
+	}
+}
+
+func TestListSecureNotesUnconfiguredProvider(t *testing.T) {
+	stream := list.ListResultsStream{}
+	(&listSecureNote{}).List(context.Background(), list.ListRequest{}, &stream)
+
+	for result := range stream.Results {
+		if !result.Diagnostics.HasError() {
+			t.Error("expected an error")
+		}
+	}
+}
+
+func TestSecureNoteImportByIdentity(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	ctx := context.Background()
+
+	identitySchema := f.identitySchema(t).IdentitySchema
+	identity := tfsdk.ResourceIdentity{
+		Schema: identitySchema,
+		Raw:    tftypes.NewValue(identitySchema.Type().TerraformType(ctx), nil),
+	}
+	if diags := identity.Set(ctx, SecureNoteIdentity{ID: getSecureNote(t, state).ID}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	resp := resource.ImportStateResponse{
+		State:    tfsdk.State{Raw: tftypes.NewValue(f.schema.Type().TerraformType(ctx), nil), Schema: f.schema},
+		Identity: &tfsdk.ResourceIdentity{Schema: identitySchema, Raw: identity.Raw.Copy()},
+	}
+	f.resource.ImportState(ctx, resource.ImportStateRequest{Identity: &identity}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if !resp.State.Raw.Equal(state.Raw) {
+		t.Errorf("expected the imported state to be\n%v\ngot\n%v", state.Raw, resp.State.Raw)
+	}
+	if !resp.Identity.Raw.Equal(identity.Raw) {
+		t.Errorf("expected the identity to be kept, got %v", resp.Identity.Raw)
+	}
+}
+
+func TestProviderServesListResources(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(New())()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+
+	if _, ok := resp.ListResourceSchemas["bitwarden_secure_note"]; !ok {
+		t.Error("expected the bitwarden_secure_note list resource")
+	}
+}
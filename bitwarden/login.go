@@ -0,0 +1,370 @@
+package bitwarden
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+// appDataDirEnv Environment variable telling the bw CLI where to keep its data (session, server config, cache...)
+const appDataDirEnv = "BITWARDENCLI_APPDATA_DIR"
+
+// dataFileName File of the CLI data directory holding the whole state of the CLI
+const dataFileName = "data.json"
+
+// appDataDirPrefix Prefix of the private CLI data directories, followed by the PID of the provider owning them
+const appDataDirPrefix = "terraform-provider-bitwarden-"
+
+// globalSessionClaims Claims of this process on the global CLI state, by lock file
+var (
+	globalSessionMu     sync.Mutex
+	globalSessionClaims = map[string]*globalSessionClaim{}
+)
+
+// secretEnvs Secrets the provider may get from its environment, they are only handed to the commands needing them
+var secretEnvs = []string{passwordEnv, "BW_CLIENTID", "BW_CLIENTSECRET"}
+
+// environment Environment of the bw commands run by this client, pointing the CLI at the private data directory
+// of the client when it has one
+func (c *Client) environment(extra ...string) []string {
+	env := lo.Filter[string](os.Environ(), func(variable string, _ int) bool {
+		name := strings.SplitN(variable, "=", 2)[0]
+		return !lo.Contains[string](secretEnvs, name) && !(c.appDataDir != "" && name == appDataDirEnv)
+	})
+	if c.appDataDir != "" {
+		env = append(env, appDataDirEnv+"="+c.appDataDir)
+	}
+
+	return append(env, extra...)
+}
+
+// globalEnvironment Environment of the bw commands acting on the global CLI state of the user
+func globalEnvironment() []string {
+	return lo.Filter[string](os.Environ(), func(variable string, _ int) bool {
+		return !lo.Contains[string](secretEnvs, strings.SplitN(variable, "=", 2)[0])
+	})
+}
+
+// globalAppDataDir CLI data directory used by bw when run by the user
+func globalAppDataDir() (string, error) {
+	if dir := os.Getenv(appDataDirEnv); dir != "" {
+		return dir, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "Bitwarden CLI"), nil
+}
+
+// isolate Gives the client its own CLI data directory, so that its session and bw serve cannot clash with the
+// ones of another provider instance or of the user. Unless the client logs in with an API key, the directory
+// starts from a copy of the global CLI state to reuse the existing "bw login".
+func (c *Client) isolate() error {
+	removeStaleAppDataDirs()
+
+	dir, err := os.MkdirTemp("", appDataDirPrefix+strconv.Itoa(os.Getpid())+"-")
+	if err != nil {
+		return err
+	}
+	c.appDataDir = dir
+
+	if c.ClientID != "" {
+		return nil
+	}
+
+	globalDir, err := globalAppDataDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(globalDir, dataFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		// Not logged in, bw will tell so
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading the state of the bitwarden CLI\n%s", err)
+	}
+
+	c.globalSessionLock, err = claimGlobalSession(globalDir, globalSessionIdentity(data, c.ServerURL))
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, dataFileName), data, 0o600)
+}
+
+// removeStaleAppDataDirs Removes the private CLI data directories of the providers that were killed before they
+// could, so that the copies of the global CLI state they hold don't outlive them
+func removeStaleAppDataDirs() {
+	dirs, _ := filepath.Glob(filepath.Join(os.TempDir(), appDataDirPrefix+"*"))
+	for _, dir := range dirs {
+		owner := strings.SplitN(strings.TrimPrefix(filepath.Base(dir), appDataDirPrefix), "-", 2)[0]
+		pid, err := strconv.Atoi(owner)
+		if err != nil || pid == os.Getpid() || processAlive(pid) {
+			continue
+		}
+
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// sessionIdentity Account and server a copy of the global CLI state is used for
+type sessionIdentity struct {
+	Account string `json:"account"`
+	Server  string `json:"server,omitempty"`
+}
+
+// globalSessionClaim Clients of this process using a copy of the global CLI state
+type globalSessionClaim struct {
+	count    int
+	identity sessionIdentity
+}
+
+// globalSessionIdentity Identity of a copy of the global CLI state: the account logged in, under the key of either
+// recent or older CLI versions, and the server the client is configured for, if any
+func globalSessionIdentity(data []byte, serverURL string) sessionIdentity {
+	var identity sessionIdentity
+
+	var state map[string]json.RawMessage
+	_ = json.Unmarshal(data, &state)
+	for _, key := range []string{"global_account_activeAccountId", "activeUserId"} {
+		var account string
+		if json.Unmarshal(state[key], &account) == nil && account != "" {
+			identity.Account = account
+			break
+		}
+	}
+
+	if serverURL != "" {
+		identity.Server = normalizeServer(serverURL)
+	}
+
+	return identity
+}
+
+// conflicts Whether two provider instances cannot share the bw login of the machine: they would use it for
+// different accounts or servers. A client without a configured server uses the one of the login.
+func (i sessionIdentity) conflicts(other sessionIdentity) bool {
+	return i.Account != other.Account || (i.Server != "" && other.Server != "" && i.Server != other.Server)
+}
+
+// claimGlobalSession Records that this process uses a copy of the global CLI state, and makes sure every other
+// provider process using one does so for the same account and server. Each process has its own lock file holding
+// the identity it claimed, clients of the same process share it.
+func claimGlobalSession(globalDir string, identity sessionIdentity) (string, error) {
+	sum := sha256.Sum256([]byte(globalDir))
+	prefix := filepath.Join(os.TempDir(), fmt.Sprintf("terraform-provider-bitwarden.%x.", sum[:8]))
+	lock := prefix + strconv.Itoa(os.Getpid()) + ".lock"
+
+	globalSessionMu.Lock()
+	defer globalSessionMu.Unlock()
+
+	if claim, ok := globalSessionClaims[lock]; ok {
+		if claim.identity.conflicts(identity) {
+			return "", globalSessionConflict(os.Getpid())
+		}
+
+		claim.count++
+		return lock, nil
+	}
+
+	others, err := filepath.Glob(prefix + "*.lock")
+	if err != nil {
+		return "", err
+	}
+	for _, other := range others {
+		pid, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(other, prefix), ".lock"))
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+		if !processAlive(pid) {
+			// Left behind by a provider that was killed
+			_ = os.Remove(other)
+			continue
+		}
+
+		content, err := os.ReadFile(other)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		var held sessionIdentity
+		if err := json.Unmarshal(content, &held); err != nil || held.conflicts(identity) {
+			return "", globalSessionConflict(pid)
+		}
+	}
+
+	content, err := json.Marshal(identity)
+	if err != nil {
+		return "", err
+	}
+	// Written aside then renamed, so that other processes never read half of it
+	if err := os.WriteFile(lock+".tmp", content, 0o600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(lock+".tmp", lock); err != nil {
+		_ = os.Remove(lock + ".tmp")
+		return "", err
+	}
+
+	globalSessionClaims[lock] = &globalSessionClaim{count: 1, identity: identity}
+	return lock, nil
+}
+
+func globalSessionConflict(pid int) error {
+	return fmt.Errorf(
+		"the bw login of this machine is already used by another provider instance (process %d) for another "+
+			"account or server, every other provider alias needs its own API key with client_id and client_secret",
+		pid,
+	)
+}
+
+// releaseGlobalSession Gives up the claim of claimGlobalSession, once no client of this process uses it anymore
+func releaseGlobalSession(lock string) error {
+	globalSessionMu.Lock()
+	defer globalSessionMu.Unlock()
+
+	claim, ok := globalSessionClaims[lock]
+	if ok {
+		claim.count--
+		if claim.count > 0 {
+			return nil
+		}
+	}
+
+	delete(globalSessionClaims, lock)
+	if err := os.Remove(lock); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// loginWithAPIKey Logs in with the API key in the private CLI data directory
+func (c *Client) loginWithAPIKey(ctx context.Context) error {
+	if c.ServerURL != "" {
+		err := c.configureServer(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The key is only given to the login command, bw serve doesn't need it once logged in
+	out, err := RunCommand(
+		ctx,
+		c.environment("BW_CLIENTID="+c.ClientID, "BW_CLIENTSECRET="+c.ClientSecret),
+		c.executable(), "login", "--apikey",
+	)
+	if err != nil {
+		return fmt.Errorf("error logging in with the API key\n%s\n%s", out, err)
+	}
+
+	c.loggedIn = true
+	return nil
+}
+
+// Close Locks the vault and logs out as configured, then releases what the client holds on to
+func (c *Client) Close(ctx context.Context) error {
+	var errs []error
+
+	if c.LockOnExit {
+		errs = append(errs, c.Lock(ctx))
+	}
+	if c.LogoutOnExit && !c.loggedIn {
+		// The session was copied from, or is served from, the global CLI state: that's where to log out
+		errs = append(errs, c.logout(ctx, globalEnvironment()))
+	}
+
+	return firstError(append(errs, c.release(ctx)))
+}
+
+// release Stops the bw serve of the client and removes its private CLI data directory, logging out first when the
+// client logged in itself with an API key
+func (c *Client) release(ctx context.Context) error {
+	var errs []error
+
+	c.stopServe()
+	c.endLifetime()
+
+	if c.loggedIn {
+		errs = append(errs, c.logout(ctx, c.environment()))
+		c.loggedIn = false
+	}
+
+	if c.appDataDir != "" {
+		errs = append(errs, os.RemoveAll(c.appDataDir))
+		c.appDataDir = ""
+	}
+
+	if c.globalSessionLock != "" {
+		errs = append(errs, releaseGlobalSession(c.globalSessionLock))
+		c.globalSessionLock = ""
+	}
+
+	return firstError(errs)
+}
+
+func (c *Client) logout(ctx context.Context, env []string) error {
+	out, err := RunCommand(ctx, env, c.executable(), "logout")
+	if err != nil {
+		return fmt.Errorf("error logging out\n%s\n%s", out, err)
+	}
+
+	return nil
+}
+
+// Lock Locks the vault of the bw serve the client used, the next call unlocks it again. A serve started by the
+// provider is stopped as well, its session key is no longer valid: the next call starts another one. On exit, it is
+// mostly useful when the serve is run outside of the provider and keeps running after Terraform is done.
+func (c *Client) Lock(ctx context.Context) error {
+	c.serveMu.Lock()
+	defer c.serveMu.Unlock()
+
+	if c.serve == nil || c.serveLocked {
+		// Never unlocked by this client, or already locked
+		return nil
+	}
+
+	resp, err := c.serve.restClient.R().SetContext(ctx).Post("/lock")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("error locking bitwarden\n%s", resp.Body())
+	}
+
+	if c.serve.Command != nil {
+		c.serve.Close()
+		c.serve = nil
+		return nil
+	}
+
+	c.serveLocked = true
+	return nil
+}
+
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
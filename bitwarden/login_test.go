@@ -0,0 +1,151 @@
+package bitwarden
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// deadPID PID of a process that has exited
+func deadPID(t *testing.T) int {
+	t.Helper()
+
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(executable, "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestRemoveStaleAppDataDirs(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	dirs := map[string]bool{
+		appDataDirPrefix + strconv.Itoa(deadPID(t)) + "-1":   false,
+		appDataDirPrefix + strconv.Itoa(os.Getppid()) + "-2": true,
+		appDataDirPrefix + strconv.Itoa(os.Getpid()) + "-3":  true,
+		appDataDirPrefix + "unknown":                         true,
+	}
+	for dir := range dirs {
+		if err := os.Mkdir(filepath.Join(tmp, dir), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removeStaleAppDataDirs()
+
+	for dir, kept := range dirs {
+		_, err := os.Stat(filepath.Join(tmp, dir))
+		if kept && err != nil {
+			t.Errorf("expected %s to be kept, got %v", dir, err)
+		}
+		if !kept && !os.IsNotExist(err) {
+			t.Errorf("expected %s of a dead provider to be removed", dir)
+		}
+	}
+}
+
+func TestClaimGlobalSession(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	globalDir := t.TempDir()
+	identity := sessionIdentity{Account: "account", Server: "https://vault.example.com"}
+
+	lock, err := claimGlobalSession(globalDir, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clients of the same process share the claim, the lock goes with the last one
+	if _, err := claimGlobalSession(globalDir, sessionIdentity{Account: "account"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := claimGlobalSession(globalDir, sessionIdentity{Account: "other"}); err == nil {
+		t.Error("expected another account to conflict within the process")
+	}
+	if err := releaseGlobalSession(lock); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lock); err != nil {
+		t.Errorf("expected the lock to be kept for the other client, got %v", err)
+	}
+	if err := releaseGlobalSession(lock); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Errorf("expected the lock to be removed, got %v", err)
+	}
+
+	// hold Claims the login for another provider process
+	hold := func(pid int, held string) string {
+		t.Helper()
+		other := strings.Replace(lock, strconv.Itoa(os.Getpid()), strconv.Itoa(pid), 1)
+		if err := os.WriteFile(other, []byte(held), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return other
+	}
+
+	// Another running provider uses it for the same account and server
+	other := hold(os.Getppid(), `{"account":"account","server":"https://vault.example.com"}`)
+	if _, err := claimGlobalSession(globalDir, identity); err != nil {
+		t.Errorf("expected the login to be shared, got %v", err)
+	}
+	if err := releaseGlobalSession(lock); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("expected the lock of the other provider to be kept, got %v", err)
+	}
+
+	// Or for another account or server
+	for _, held := range []string{
+		`{"account":"other","server":"https://vault.example.com"}`,
+		`{"account":"account","server":"https://vault.bitwarden.eu"}`,
+	} {
+		hold(os.Getppid(), held)
+		if _, err := claimGlobalSession(globalDir, identity); err == nil || !strings.Contains(err.Error(), "client_id") {
+			t.Errorf("expected the API key to be required next to %s, got %v", held, err)
+		}
+	}
+
+	// A killed provider left it behind
+	if err := os.Remove(other); err != nil {
+		t.Fatal(err)
+	}
+	other = hold(deadPID(t), `{"account":"other"}`)
+	if _, err := claimGlobalSession(globalDir, identity); err != nil {
+		t.Fatalf("expected the lock of a dead provider to be ignored, got %v", err)
+	}
+	if _, err := os.Stat(other); !os.IsNotExist(err) {
+		t.Errorf("expected the lock of the dead provider to be removed, got %v", err)
+	}
+	if err := releaseGlobalSession(lock); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlobalSessionIdentity(t *testing.T) {
+	tests := []struct {
+		data      string
+		serverURL string
+		expected  sessionIdentity
+	}{
+		{`{"global_account_activeAccountId":"recent"}`, "", sessionIdentity{Account: "recent"}},
+		{`{"activeUserId":"older"}`, "https://bitwarden.com/", sessionIdentity{Account: "older", Server: "https://vault.bitwarden.com"}},
+		{`not json`, "", sessionIdentity{}},
+	}
+
+	for _, test := range tests {
+		if actual := globalSessionIdentity([]byte(test.data), test.serverURL); actual != test.expected {
+			t.Errorf("%s: expected %+v, got %+v", test.data, test.expected, actual)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package bitwarden
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	ephemeraltimeouts "github.com/hashicorp/terraform-plugin-framework-timeouts/ephemeral/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 // SecureNote Represents the "bitwarden_secure_note" resource
 type SecureNote struct {
@@ -8,11 +12,60 @@ type SecureNote struct {
 	ID             types.String `tfsdk:"id"`
 	OrganizationId types.String `tfsdk:"organization_id"`
 	FolderID       types.String `tfsdk:"folder_id"`
-	Type           types.Number `tfsdk:"type"`
+	Type           types.Int64  `tfsdk:"type"`
 	Reprompt       types.Bool   `tfsdk:"reprompt"`
 	Name           types.String `tfsdk:"name"`
 	Notes          types.String `tfsdk:"notes"`
+	// NotesWO Only read from the configuration, on creation and when notes_wo_version changes
+	NotesWO        types.String `tfsdk:"notes_wo"`
+	NotesWOVersion types.Int64  `tfsdk:"notes_wo_version"`
 	Favorite       types.Bool   `tfsdk:"favorite"`
 	CollectionIDs  []string     `tfsdk:"collection_ids"`
 	RevisionDate   types.String `tfsdk:"revision_date"`
+	// DeletionProtection Only known to Terraform, it makes Delete fail until turned off
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// SecureNoteIdentity Identity of a "bitwarden_secure_note" resource, used by import blocks and terraform query
+type SecureNoteIdentity struct {
+	ID types.String `tfsdk:"id"`
+}
+
+// writeOnlyNotes Whether the notes are written with notes_wo instead of notes
+func (n SecureNote) writeOnlyNotes() bool {
+	return n.Notes.IsNull() && !n.NotesWOVersion.IsNull()
+}
+
+// EphemeralItem Represents the "bitwarden_item" ephemeral resource
+type EphemeralItem struct {
+	ID             types.String         `tfsdk:"id"`
+	OrganizationId types.String         `tfsdk:"organization_id"`
+	Type           types.Int64          `tfsdk:"type"`
+	Name           types.String         `tfsdk:"name"`
+	Notes          types.String         `tfsdk:"notes"`
+	Login          *EphemeralItemLogin  `tfsdk:"login"`
+	Fields         []EphemeralItemField `tfsdk:"fields"`
+	// Timeouts Only read from the configuration, and copied to the result
+	Timeouts ephemeraltimeouts.Value `tfsdk:"timeouts"`
+}
+
+// EphemeralItemLogin Username, password, TOTP secret and URIs of a login item
+type EphemeralItemLogin struct {
+	Username types.String            `tfsdk:"username"`
+	Password types.String            `tfsdk:"password"`
+	TOTP     types.String            `tfsdk:"totp"`
+	URIs     []EphemeralItemLoginURI `tfsdk:"uris"`
+}
+
+type EphemeralItemLoginURI struct {
+	URI   types.String `tfsdk:"uri"`
+	Match types.Int64  `tfsdk:"match"`
+}
+
+// EphemeralItemField Custom field of an item
+type EphemeralItemField struct {
+	Name  types.String `tfsdk:"name"`
+	Value types.String `tfsdk:"value"`
+	Type  types.Int64  `tfsdk:"type"`
 }
@@ -0,0 +1,77 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// planItemMove Warns about a change of the organization of an item. BitWarden moves personal items to an
+// organization, but never moves an item out of its organization, so leaving one is planned as a replacement.
+func planItemMove(
+	ctx context.Context,
+	plan tfsdk.Plan,
+	state tfsdk.State,
+	resource string,
+	requiresReplace *path.Paths,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if state.Raw.IsNull() {
+		return diags
+	}
+
+	organizationPath := path.Root("organization_id")
+
+	var from, to, id types.String
+	diags.Append(plan.GetAttribute(ctx, organizationPath, &to)...)
+	diags.Append(state.GetAttribute(ctx, organizationPath, &from)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("id"), &id)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if !to.IsUnknown() && from.ValueString() == to.ValueString() {
+		return diags
+	}
+
+	name := fmt.Sprintf("%s %s", resource, id.ValueString())
+
+	destination := "the personal vault"
+	if to.IsUnknown() {
+		destination = "an organization known after apply"
+	} else if to.ValueString() != "" {
+		destination = fmt.Sprintf("organization %s", to.ValueString())
+	}
+
+	if from.ValueString() == "" {
+		diags.AddAttributeWarning(
+			organizationPath,
+			"Item moving to an organization",
+			fmt.Sprintf(
+				"%s moves from the personal vault to %s, which then owns it. BitWarden cannot move it back, "+
+					"taking it out of the organization later means replacing it.",
+				name,
+				destination,
+			),
+		)
+		return diags
+	}
+
+	*requiresReplace = append(*requiresReplace, organizationPath)
+	diags.AddAttributeWarning(
+		organizationPath,
+		"Item leaving its organization",
+		fmt.Sprintf(
+			"BitWarden cannot move %s out of organization %s, it is going to be deleted and re-created in %s, "+
+				"with a new ID and without its password history.",
+			name,
+			from.ValueString(),
+			destination,
+		),
+	)
+	return diags
+}
@@ -0,0 +1,203 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// passwordCommandTimeout Time given to a credential helper to print the master password
+const passwordCommandTimeout = 30 * time.Second
+
+// passwordEnv Environment variable standing for the password attribute
+const passwordEnv = "BW_PASSWORD"
+
+// passwordAttributes Provider attributes the master password can come from, only one of them can be set
+var passwordAttributes = []string{"password", "password_file", "password_command"}
+
+// masterPassword Resolves the master password from the configured source, or from BW_PASSWORD when none is
+// configured. With password_file, the path is also returned so that bw unlock can read the file itself.
+func (config providerData) masterPassword(ctx context.Context) (string, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if config.Password.IsUnknown() || config.PasswordFile.IsUnknown() || config.PasswordCommand.IsUnknown() {
+		// Cannot connect to client with an unknown value
+		diags.AddError(
+			"Unable to create client",
+			"Cannot use unknown value as password",
+		)
+		return "", "", diags
+	}
+
+	switch {
+	case !config.PasswordFile.IsNull():
+		content, err := os.ReadFile(config.PasswordFile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("password_file"),
+				"Unable to read password file",
+				err.Error(),
+			)
+			return "", "", diags
+		}
+		return strings.TrimRight(string(content), "\r\n"), config.PasswordFile.ValueString(), diags
+	case !config.PasswordCommand.IsNull():
+		var command []string
+		diags.Append(config.PasswordCommand.ElementsAs(ctx, &command, false)...)
+		if diags.HasError() {
+			return "", "", diags
+		}
+
+		password, err := runPasswordCommand(ctx, command)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("password_command"),
+				"Unable to get password from password_command",
+				err.Error(),
+			)
+			return "", "", diags
+		}
+		return password, "", diags
+	case !config.Password.IsNull():
+		return config.Password.ValueString(), "", diags
+	}
+
+	// The validator only let the configuration leave out the password because of the environment
+	return os.Getenv(passwordEnv), "", diags
+}
+
+// runPasswordCommand Runs a credential helper and returns what it printed, without the surrounding whitespace
+func runPasswordCommand(ctx context.Context, command []string) (string, error) {
+	if len(command) == 0 {
+		return "", fmt.Errorf("password_command cannot be empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, passwordCommandTimeout)
+	defer cancel()
+
+	stdout, stderr, err := RunCommandOutput(ctx, nil, command[0], command[1:]...)
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s did not answer within %s", command[0], passwordCommandTimeout)
+	}
+	if err != nil {
+		// Only stderr is shown, stdout could hold (part of) the password
+		return "", fmt.Errorf("%s failed\n%s\n%s", command[0], stderr, err)
+	}
+
+	return strings.TrimSpace(stdout), nil
+}
+
+// conflictingAttributesValidator Makes sure at most one of the given attributes is set, or exactly one of them when
+// one is required. It checks the configuration of the provider as well as the one of a resource.
+type conflictingAttributesValidator struct {
+	attributes []string
+	// required One of the attributes must be set, unless one of the fallbacks is
+	required bool
+	// fallbackAttributes and fallbackEnvs Attributes and environment variables that do without the attributes
+	fallbackAttributes []string
+	fallbackEnvs       []string
+}
+
+func (v conflictingAttributesValidator) Description(_ context.Context) string {
+	if v.required {
+		return fmt.Sprintf("exactly one of %s must be set", strings.Join(v.attributes, ", "))
+	}
+	return fmt.Sprintf("only one of %s can be set", strings.Join(v.attributes, ", "))
+}
+
+func (v conflictingAttributesValidator) MarkdownDescription(_ context.Context) string {
+	if v.required {
+		return fmt.Sprintf("exactly one of `%s` must be set", strings.Join(v.attributes, "`, `"))
+	}
+	return fmt.Sprintf("only one of `%s` can be set", strings.Join(v.attributes, "`, `"))
+}
+
+func (v conflictingAttributesValidator) ValidateProvider(
+	ctx context.Context,
+	req tfprovider.ValidateConfigRequest,
+	resp *tfprovider.ValidateConfigResponse,
+) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config, "provider")...)
+}
+
+func (v conflictingAttributesValidator) ValidateResource(
+	ctx context.Context,
+	req resource.ValidateConfigRequest,
+	resp *resource.ValidateConfigResponse,
+) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config, "resource")...)
+}
+
+func (v conflictingAttributesValidator) validate(ctx context.Context, config tfsdk.Config, kind string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var set []string
+
+	for _, name := range v.attributes {
+		var value attr.Value
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		// Unknown values count as set, they will be by the time the provider is configured
+		if !value.IsNull() {
+			set = append(set, name)
+		}
+	}
+
+	switch {
+	case len(set) > 1:
+		diags.AddAttributeError(
+			path.Root(set[1]),
+			fmt.Sprintf("Conflicting %s configuration", kind),
+			fmt.Sprintf("Only one of %s can be set, got %s", strings.Join(v.attributes, ", "), strings.Join(set, " and ")),
+		)
+	case len(set) == 0 && v.required:
+		fallback, fallbackDiags := v.hasFallback(ctx, config)
+		diags.Append(fallbackDiags...)
+		if fallback || diags.HasError() {
+			return diags
+		}
+
+		message := fmt.Sprintf("One of %s must be set", strings.Join(v.attributes, ", "))
+		if fallbacks := append(append([]string{}, v.fallbackAttributes...), v.fallbackEnvs...); len(fallbacks) > 0 {
+			message += fmt.Sprintf(", unless %s is", strings.Join(fallbacks, " or "))
+		}
+		diags.AddError(fmt.Sprintf("Incomplete %s configuration", kind), message)
+	}
+
+	return diags
+}
+
+// hasFallback Whether one of the fallback attributes or environment variables is set
+func (v conflictingAttributesValidator) hasFallback(ctx context.Context, config tfsdk.Config) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	for _, name := range v.fallbackAttributes {
+		var value attr.Value
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+		if diags.HasError() {
+			return false, diags
+		}
+		if !value.IsNull() {
+			return true, diags
+		}
+	}
+
+	for _, name := range v.fallbackEnvs {
+		if os.Getenv(name) != "" {
+			return true, diags
+		}
+	}
+
+	return false, diags
+}
@@ -0,0 +1,161 @@
+package bitwarden
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// emptyProviderData Provider configuration with every attribute unset
+func emptyProviderData() providerData {
+	return providerData{
+		Password:              types.StringNull(),
+		PasswordFile:          types.StringNull(),
+		PasswordCommand:       types.ListNull(types.StringType),
+		BwExecutable:          types.StringNull(),
+		BwServePort:           types.Int64Null(),
+		ClientID:              types.StringNull(),
+		ClientSecret:          types.StringNull(),
+		ServerURL:             types.StringNull(),
+		Region:                types.StringNull(),
+		Transport:             types.StringNull(),
+		SessionKey:            types.StringNull(),
+		ReadOnly:              types.BoolNull(),
+		DefaultOrganizationID: types.StringNull(),
+		DefaultCollectionIDs:  types.ListNull(types.StringType),
+		DefaultFolderID:       types.StringNull(),
+		RestoreTrashedItems:   types.BoolNull(),
+		LockOnExit:            types.BoolNull(),
+		LogoutOnExit:          types.BoolNull(),
+	}
+}
+
+func TestMasterPasswordFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("  from file \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := emptyProviderData()
+	config.PasswordFile = types.StringValue(path)
+
+	password, passwordFile, diags := config.masterPassword(context.Background())
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if password != "  from file " {
+		t.Errorf("expected only the trailing newline to be removed, got %q", password)
+	}
+	if passwordFile != path {
+		t.Errorf("expected the password file %q to be returned, got %q", path, passwordFile)
+	}
+}
+
+func TestMasterPasswordFromCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on echo")
+	}
+
+	config := emptyProviderData()
+	config.PasswordCommand = types.ListValueMust(
+		types.StringType,
+		[]attr.Value{types.StringValue("echo"), types.StringValue(" from command ")},
+	)
+
+	password, passwordFile, diags := config.masterPassword(context.Background())
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if password != "from command" {
+		t.Errorf("expected the output of the command to be trimmed, got %q", password)
+	}
+	if passwordFile != "" {
+		t.Errorf("expected no password file, got %q", passwordFile)
+	}
+
+	config.PasswordCommand = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("false")})
+	if _, _, diags := config.masterPassword(context.Background()); !diags.HasError() {
+		t.Error("expected a failing command to be reported")
+	}
+}
+
+func TestMasterPasswordFromEnvironment(t *testing.T) {
+	t.Setenv("BW_PASSWORD", "from env")
+
+	password, _, diags := emptyProviderData().masterPassword(context.Background())
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if password != "from env" {
+		t.Errorf("expected the password from BW_PASSWORD, got %q", password)
+	}
+}
+
+// validateProvider Runs the validators of the provider configuration, returns whether it is valid
+func validateProvider(t *testing.T, config providerData) bool {
+	t.Helper()
+
+	ctx := context.Background()
+	schema := tfprovider.SchemaResponse{}
+	(&provider{}).Schema(ctx, tfprovider.SchemaRequest{}, &schema)
+
+	state := tfsdk.State{Schema: schema.Schema}
+	if diags := state.Set(ctx, config); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	resp := tfprovider.ValidateConfigResponse{}
+	for _, v := range (&provider{}).ConfigValidators(ctx) {
+		v.ValidateProvider(
+			ctx,
+			tfprovider.ValidateConfigRequest{Config: tfsdk.Config{Raw: state.Raw, Schema: schema.Schema}},
+			&resp,
+		)
+	}
+	return !resp.Diagnostics.HasError()
+}
+
+func TestPasswordSourcesConflict(t *testing.T) {
+
+	config := emptyProviderData()
+	config.Password = types.StringValue("password")
+	if !validateProvider(t, config) {
+		t.Error("expected a single password source to be valid")
+	}
+
+	config.PasswordFile = types.StringUnknown()
+	if validateProvider(t, config) {
+		t.Error("expected password and password_file to conflict")
+	}
+}
+
+func TestPasswordSourceRequired(t *testing.T) {
+	t.Setenv("BW_PASSWORD", "")
+	t.Setenv("BW_SESSION", "")
+
+	if validateProvider(t, emptyProviderData()) {
+		t.Error("expected a configuration without password to be rejected")
+	}
+
+	withSessionKey := emptyProviderData()
+	withSessionKey.SessionKey = types.StringValue("session")
+	if !validateProvider(t, withSessionKey) {
+		t.Error("expected a session key to do without password")
+	}
+
+	for _, env := range []string{"BW_PASSWORD", "BW_SESSION"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, "from env")
+			if !validateProvider(t, emptyProviderData()) {
+				t.Errorf("expected %s to do without password", env)
+			}
+		})
+	}
+}
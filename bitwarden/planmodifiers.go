@@ -0,0 +1,64 @@
+package bitwarden
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// itemAttributes Attributes of an item resource stored in BitWarden, as opposed to the ones only Terraform knows
+// about such as deletion_protection and timeouts. notes_wo_version stands for notes_wo, which plans never hold.
+var itemAttributes = []string{
+	"organization_id",
+	"collection_ids",
+	"folder_id",
+	"name",
+	"notes",
+	"notes_wo_version",
+	"favorite",
+	"reprompt",
+}
+
+// attributeGetter Reads attributes of a plan or a state
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+// itemChanged Whether the plan changes the item in BitWarden, unknown values count as changes
+func itemChanged(ctx context.Context, plan attributeGetter, state attributeGetter) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	for _, name := range itemAttributes {
+		var planned, prior attr.Value
+		diags.Append(plan.GetAttribute(ctx, path.Root(name), &planned)...)
+		diags.Append(state.GetAttribute(ctx, path.Root(name), &prior)...)
+		if diags.HasError() {
+			return false, diags
+		}
+
+		if !planned.Equal(prior) {
+			return true, diags
+		}
+	}
+
+	return false, diags
+}
+
+// planRevisionDate Keeps the revision date of an item unless the plan changes the item in BitWarden
+func planRevisionDate(ctx context.Context, plan *tfsdk.Plan, state tfsdk.State) diag.Diagnostics {
+	if state.Raw.IsNull() {
+		return nil
+	}
+
+	changed, diags := itemChanged(ctx, plan, state)
+	if diags.HasError() || !changed {
+		return diags
+	}
+
+	diags.Append(plan.SetAttribute(ctx, path.Root("revision_date"), types.StringUnknown())...)
+	return diags
+}
@@ -0,0 +1,18 @@
+package bitwarden
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// processAlive Whether a process with this PID is running. Signal 0 only checks that the process exists, Windows
+// doesn't support it but only finds running processes.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	return !errors.Is(process.Signal(syscall.Signal(0)), os.ErrProcessDone)
+}
@@ -0,0 +1,12 @@
+package bitwarden
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// bindToProvider Has the kernel kill the process when the provider dies, even from a SIGKILL that leaves the provider
+// no time to stop it
+func bindToProvider(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package bitwarden
+
+import "os/exec"
+
+// bindToProvider Only Linux can tie a process to the lifetime of its parent, elsewhere the process is stopped with
+// the client
+func bindToProvider(_ *exec.Cmd) {}
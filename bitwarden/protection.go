@@ -0,0 +1,43 @@
+package bitwarden
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// checkWritable Reports a change planned or attempted with a read-only provider, returns false in that case
+func (p provider) checkWritable(diags *diag.Diagnostics, action string, resource string) bool {
+	if !p.readOnly {
+		return true
+	}
+
+	diags.AddError(
+		"Read-only provider",
+		fmt.Sprintf(
+			"Cannot %s %s: the provider is configured with read_only = true, which only allows reading the vault.",
+			action,
+			resource,
+		),
+	)
+	return false
+}
+
+// checkDeletionProtection Reports the deletion of a protected item, returns false in that case
+func checkDeletionProtection(diags *diag.Diagnostics, deletionProtection types.Bool, resource string) bool {
+	if !deletionProtection.ValueBool() {
+		return true
+	}
+
+	diags.AddAttributeError(
+		path.Root("deletion_protection"),
+		"Deletion protection",
+		fmt.Sprintf(
+			"Cannot delete %s while deletion_protection is enabled, set it to false and apply before deleting it.",
+			resource,
+		),
+	)
+	return false
+}
@@ -3,49 +3,212 @@ package bitwarden
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/samber/lo"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
-func New() tfsdk.Provider {
+func New() tfprovider.Provider {
 	return &provider{}
 }
 
 type provider struct {
-	configured bool
-	client     *Client
+	configured          bool
+	client              Vault
+	restoreTrashedItems bool
+	defaults            itemDefaults
+	// readOnly Rejects any change to the vault
+	readOnly bool
+	// directory Organizations, collections and folders the plans are checked against
+	directory *vaultDirectory
+	// openEphemeralItems Item ephemeral resources left to close before locking the vault
+	openEphemeralItems *openEphemeralItems
 }
 
-func (p *provider) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
-	return tfsdk.Schema{
-		Attributes: map[string]tfsdk.Attribute{
-			"password": {
-				Type:      types.StringType,
+func (p *provider) Metadata(
+	_ context.Context,
+	_ tfprovider.MetadataRequest,
+	response *tfprovider.MetadataResponse,
+) {
+	response.TypeName = "bitwarden"
+}
+
+func (p *provider) Schema(_ context.Context, _ tfprovider.SchemaRequest, response *tfprovider.SchemaResponse) {
+	response.Schema = providerschema.Schema{
+		Attributes: map[string]providerschema.Attribute{
+			"password": providerschema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+			},
+			// File holding the master password, read by bw unlock itself when possible
+			"password_file": providerschema.StringAttribute{
+				Optional: true,
+			},
+			// Credential helper printing the master password, as a list of arguments, e.g. ["pass", "bitwarden"]
+			"password_command": providerschema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			// Path of the bw CLI, defaults to bw from the PATH
+			"bw_executable": providerschema.StringAttribute{
+				Optional: true,
+			},
+			"bw_serve_port": providerschema.Int64Attribute{
+				Optional: true,
+			},
+			// API key to log in with, in a CLI data directory private to the provider
+			"client_id": providerschema.StringAttribute{
+				Optional: true,
+			},
+			"client_secret": providerschema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+			},
+			// BitWarden server, for self-hosted instances, defaults to the server the CLI is configured with
+			"server_url": providerschema.StringAttribute{
+				Optional: true,
+			},
+			// Shorthand for the server of a BitWarden cloud region, "US" or "EU"
+			"region": providerschema.StringAttribute{
+				Optional: true,
+			},
+			// How the provider talks to BitWarden: "serve" (default) runs bw serve, "cli" runs a bw command per operation
+			"transport": providerschema.StringAttribute{
+				Optional: true,
+			},
+			// Session key of an unlocked vault (bw unlock --raw), only used by the "cli" transport
+			"session_key": providerschema.StringAttribute{
 				Optional:  true,
-				Computed:  false,
 				Sensitive: true,
 			},
-			"bw_serve_port": {
-				Type:     types.Int64Type,
+			// Only allow reading the vault, creating, updating or deleting items fails
+			"read_only": providerschema.BoolAttribute{
+				Optional: true,
+			},
+			// Organization, collections and folder of the items whose resource leaves them out
+			"default_organization_id": providerschema.StringAttribute{
+				Optional:   true,
+				Validators: []validator.String{uuidValidator{}},
+			},
+			"default_collection_ids": providerschema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators:  []validator.List{uuidValidator{}},
+			},
+			"default_folder_id": providerschema.StringAttribute{
+				Optional:   true,
+				Validators: []validator.String{uuidValidator{}},
+			},
+			// Restore items found in the trash instead of planning to re-create them, defaults to false
+			"restore_trashed_items": providerschema.BoolAttribute{
+				Optional: true,
+			},
+			"sync": providerschema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]providerschema.Attribute{
+					// When to sync the vault: "always", "once_per_run" (default), "if_older_than" or "never"
+					"mode": providerschema.StringAttribute{
+						Required: true,
+					},
+					// Age of the last sync from which the vault gets synced with "if_older_than", e.g. "15m"
+					"max_age": providerschema.StringAttribute{
+						Optional: true,
+					},
+				},
+			},
+			// Lock the vault when the provider shuts down, e.g. a bw serve run outside of Terraform
+			"lock_on_exit": providerschema.BoolAttribute{
+				Optional: true,
+			},
+			// Log out of the global CLI session when the provider shuts down. That's the bw login of the machine, not a
+			// session of the provider: every other use of bw must log in again.
+			"logout_on_exit": providerschema.BoolAttribute{
 				Optional: true,
-				Computed: false,
+			},
+			"retry": providerschema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]providerschema.Attribute{
+					// Total number of attempts for a call to BitWarden, including the first one
+					"max_attempts": providerschema.Int64Attribute{
+						Optional: true,
+					},
+					// Delay before the first retry, doubled on every attempt, as a Go duration (e.g. "1s")
+					"base_delay": providerschema.StringAttribute{
+						Optional: true,
+					},
+					// Upper bound of the delay between two attempts, as a Go duration (e.g. "30s")
+					"max_delay": providerschema.StringAttribute{
+						Optional: true,
+					},
+				},
 			},
 		},
-	}, nil
+	}
+}
+
+func (p *provider) ConfigValidators(_ context.Context) []tfprovider.ConfigValidator {
+	return []tfprovider.ConfigValidator{
+		// A session key unlocks the vault without password, with the "cli" transport
+		conflictingAttributesValidator{
+			attributes:         passwordAttributes,
+			required:           true,
+			fallbackAttributes: []string{"session_key"},
+			fallbackEnvs:       []string{passwordEnv, sessionEnv},
+		},
+	}
 }
 
 type providerData struct {
-	Password    types.String `tfsdk:"password"`
-	BwServePort types.Int64  `tfsdk:"bw_serve_port"`
+	Password              types.String       `tfsdk:"password"`
+	PasswordFile          types.String       `tfsdk:"password_file"`
+	PasswordCommand       types.List         `tfsdk:"password_command"`
+	BwExecutable          types.String       `tfsdk:"bw_executable"`
+	BwServePort           types.Int64        `tfsdk:"bw_serve_port"`
+	ClientID              types.String       `tfsdk:"client_id"`
+	ClientSecret          types.String       `tfsdk:"client_secret"`
+	ServerURL             types.String       `tfsdk:"server_url"`
+	Region                types.String       `tfsdk:"region"`
+	Transport             types.String       `tfsdk:"transport"`
+	SessionKey            types.String       `tfsdk:"session_key"`
+	ReadOnly              types.Bool         `tfsdk:"read_only"`
+	DefaultOrganizationID types.String       `tfsdk:"default_organization_id"`
+	DefaultCollectionIDs  types.List         `tfsdk:"default_collection_ids"`
+	DefaultFolderID       types.String       `tfsdk:"default_folder_id"`
+	RestoreTrashedItems   types.Bool         `tfsdk:"restore_trashed_items"`
+	LockOnExit            types.Bool         `tfsdk:"lock_on_exit"`
+	LogoutOnExit          types.Bool         `tfsdk:"logout_on_exit"`
+	Retry                 *providerRetryData `tfsdk:"retry"`
+	Sync                  *providerSyncData  `tfsdk:"sync"`
+}
+
+type providerSyncData struct {
+	Mode   types.String `tfsdk:"mode"`
+	MaxAge types.String `tfsdk:"max_age"`
+}
+
+type providerRetryData struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	BaseDelay   types.String `tfsdk:"base_delay"`
+	MaxDelay    types.String `tfsdk:"max_delay"`
 }
 
 func (p *provider) Configure(
 	ctx context.Context,
-	request tfsdk.ConfigureProviderRequest,
-	response *tfsdk.ConfigureProviderResponse,
+	request tfprovider.ConfigureRequest,
+	response *tfprovider.ConfigureResponse,
 ) {
 	// Retrieve provider data from configuration
 	var config providerData
@@ -55,35 +218,41 @@ func (p *provider) Configure(
 		return
 	}
 
-	// User must provide a password to the provider
-	var password string
-	if config.Password.Unknown {
-		// Cannot connect to client with an unknown value
-		response.Diagnostics.AddError(
-			"Unable to create client",
-			"Cannot use unknown value as password",
-		)
+	transport := stringFromConfigOrEnv(config.Transport, "BW_TRANSPORT", "transport", &response.Diagnostics)
+	sessionKey := stringFromConfigOrEnv(config.SessionKey, "BW_SESSION", "session_key", &response.Diagnostics)
+	if response.Diagnostics.HasError() {
 		return
 	}
 
-	// If password is not provided in the config, try to get it from the environment
-	if config.Password.Null {
-		password = os.Getenv("BW_PASSWORD")
-	} else {
-		password = config.Password.Value
+	// User must provide a password to the provider
+	password, passwordFile, diags := config.masterPassword(ctx)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
 	}
 
-	if password == "" {
+	// A session key of an unlocked vault can replace the password with the "cli" transport
+	if password == "" && !(transport == "cli" && sessionKey != "") {
 		// Cannot continue without a password
 		response.Diagnostics.AddError(
 			"Unable to find password",
-			"password cannot be an empty string",
+			fmt.Sprintf(
+				"One of %s or the %s environment variable must provide the master password, "+
+					"it can only be left out with a session_key and the \"cli\" transport",
+				strings.Join(passwordAttributes, ", "),
+				passwordEnv,
+			),
 		)
 		return
 	}
 
+	executable := stringFromConfigOrEnv(config.BwExecutable, "BW_EXECUTABLE", "bw_executable", &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	bwServePort := int64(0)
-	if config.BwServePort.Unknown {
+	if config.BwServePort.IsUnknown() {
 		// Cannot connect to client with an unknown value
 		response.Diagnostics.AddError(
 			"Unable to create client",
@@ -92,7 +261,7 @@ func (p *provider) Configure(
 		return
 	}
 
-	if config.BwServePort.Null {
+	if config.BwServePort.IsNull() {
 		port := os.Getenv("BW_SERVE_PORT")
 		if port != "" {
 			p, err := strconv.Atoi(port)
@@ -105,29 +274,347 @@ func (p *provider) Configure(
 			bwServePort = int64(p)
 		}
 	} else {
-		bwServePort = config.BwServePort.Value
+		bwServePort = config.BwServePort.ValueInt64()
 	}
 
-	// Create a new BitWarden client and set it to the provider client
-	c, err := NewClient(password, bwServePort)
-	if err != nil {
+	clientId := stringFromConfigOrEnv(config.ClientID, "BW_CLIENTID", "client_id", &response.Diagnostics)
+	clientSecret := stringFromConfigOrEnv(config.ClientSecret, "BW_CLIENTSECRET", "client_secret", &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if (clientId == "") != (clientSecret == "") {
+		response.Diagnostics.AddError(
+			"Incomplete API key",
+			"client_id and client_secret must be provided together",
+		)
+		return
+	}
+
+	if clientId != "" && bwServePort != 0 {
+		// We would be logging in a CLI data directory the external bw serve doesn't use
 		response.Diagnostics.AddError(
 			"Unable to create client",
-			fmt.Sprintf("Unable to create BitWarden client: %s", err.Error()),
+			"client_id and client_secret cannot be used with bw_serve_port, the provider must run bw serve itself",
+		)
+		return
+	}
+
+	serverURL, diags := config.serverURL()
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	retry, diags := config.retryPolicy()
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	syncPolicy, diags := config.syncPolicy()
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	defaults, diags := config.itemDefaults(ctx)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if config.LogoutOnExit.ValueBool() && clientId == "" {
+		response.Diagnostics.AddAttributeWarning(
+			path.Root("logout_on_exit"),
+			"Logging out of the global bw session",
+			"logout_on_exit logs out of the bw login of this machine when Terraform is done, "+
+				"every other use of bw will have to log in again. Use client_id and client_secret for a session "+
+				"of the provider's own, which is always logged out.",
+		)
+	}
+
+	clientConfig := ClientConfig{
+		Password:     password,
+		PasswordFile: passwordFile,
+		Port:         bwServePort,
+		Retry:        retry,
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		ServerURL:    serverURL,
+		Executable:   executable,
+		SyncPolicy:   syncPolicy,
+		LockOnExit:   config.LockOnExit.ValueBool(),
+		LogoutOnExit: config.LogoutOnExit.ValueBool(),
+	}
+
+	// Create a new BitWarden client and set it to the provider client
+	switch transport {
+	case "", "serve":
+		c, err := NewClient(ctx, clientConfig)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Unable to create client",
+				fmt.Sprintf("Unable to create BitWarden client: %s", err.Error()),
+			)
+			return
+		}
+
+		registerClient(c)
+		p.client = c
+	case "cli":
+		if bwServePort != 0 {
+			response.Diagnostics.AddAttributeError(
+				path.Root("bw_serve_port"),
+				"Unable to create client",
+				"bw_serve_port cannot be used with the \"cli\" transport",
+			)
+			return
+		}
+
+		c, err := NewCLIClient(ctx, clientConfig, sessionKey)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Unable to create client",
+				fmt.Sprintf("Unable to create BitWarden client: %s", err.Error()),
+			)
+			return
+		}
+
+		registerClient(c)
+		p.client = c
+	default:
+		response.Diagnostics.AddAttributeError(
+			path.Root("transport"),
+			"Unknown transport",
+			fmt.Sprintf("transport must be one of \"serve\" or \"cli\", got %q", transport),
 		)
 		return
 	}
 
-	p.client = c
+	p.restoreTrashedItems = config.RestoreTrashedItems.ValueBool()
+	p.defaults = defaults
+	p.directory = newVaultDirectory()
+	p.openEphemeralItems = &openEphemeralItems{}
+	p.readOnly = config.ReadOnly.ValueBool()
 	p.configured = true
+
+	// Resources get the configured provider through their Configure method
+	response.ResourceData = p
+	response.EphemeralResourceData = p
+	response.ListResourceData = p
+}
+
+// stringFromConfigOrEnv Returns the configured value of an attribute, falling back to an environment variable
+func stringFromConfigOrEnv(value types.String, env string, name string, diags *diag.Diagnostics) string {
+	if value.IsUnknown() {
+		// Cannot connect to client with an unknown value
+		diags.AddAttributeError(
+			path.Root(name),
+			"Unable to create client",
+			fmt.Sprintf("Cannot use unknown value as %s", name),
+		)
+		return ""
+	}
+
+	if value.IsNull() {
+		return os.Getenv(env)
+	}
+
+	return value.ValueString()
+}
+
+func (p *provider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newResourceSecureNote,
+	}
 }
 
-func (p *provider) GetResources(_ context.Context) (map[string]tfsdk.ResourceType, diag.Diagnostics) {
-	return map[string]tfsdk.ResourceType{
-		"bitwarden_secure_note": resourceSecureNoteType{},
-	}, nil
+func (p *provider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// ListResources Item types terraform query can list, each one needs a managed resource to generate its configuration
+// for, which only secure notes have so far
+func (p *provider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		newListSecureNote,
+	}
+}
+
+func (p *provider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		newFunctionParseOTPAuth,
+		newFunctionBuildOTPAuth,
+		newFunctionURIMatches,
+	}
 }
 
-func (p *provider) GetDataSources(_ context.Context) (map[string]tfsdk.DataSourceType, diag.Diagnostics) {
-	return map[string]tfsdk.DataSourceType{}, nil
+func (p *provider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newEphemeralItem,
+	}
+}
+
+// serverURL Resolves the BitWarden server from either server_url or region
+func (config providerData) serverURL() (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	serverURL := stringFromConfigOrEnv(config.ServerURL, "BW_SERVER_URL", "server_url", &diags)
+	if config.Region.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("region"),
+			"Unable to create client",
+			"Cannot use unknown value as region",
+		)
+	}
+	if diags.HasError() || config.Region.IsNull() {
+		return serverURL, diags
+	}
+
+	if !config.ServerURL.IsNull() {
+		diags.AddAttributeError(
+			path.Root("region"),
+			"Conflicting server configuration",
+			"Only one of server_url and region can be set",
+		)
+		return "", diags
+	}
+
+	regionURL, ok := regionServerURLs[strings.ToUpper(config.Region.ValueString())]
+	if !ok {
+		diags.AddAttributeError(
+			path.Root("region"),
+			"Unknown region",
+			fmt.Sprintf("region must be one of \"US\" or \"EU\", got %q", config.Region.ValueString()),
+		)
+		return "", diags
+	}
+
+	return regionURL, diags
+}
+
+// retryPolicy Builds the retry policy from the "retry" block, keeping the defaults for unset values
+func (config providerData) retryPolicy() (RetryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	policy := DefaultRetryPolicy
+
+	if config.Retry == nil {
+		return policy, diags
+	}
+
+	if config.Retry.MaxAttempts.IsUnknown() || config.Retry.BaseDelay.IsUnknown() || config.Retry.MaxDelay.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("retry"),
+			"Unable to create client",
+			"Cannot use unknown value in retry",
+		)
+		return policy, diags
+	}
+
+	if !config.Retry.MaxAttempts.IsNull() {
+		if config.Retry.MaxAttempts.ValueInt64() < 1 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName("max_attempts"),
+				"Invalid retry configuration",
+				"max_attempts must be at least 1",
+			)
+		}
+		policy.MaxAttempts = config.Retry.MaxAttempts.ValueInt64()
+	}
+
+	for name, setting := range map[string]struct {
+		value  types.String
+		target *time.Duration
+	}{
+		"base_delay": {config.Retry.BaseDelay, &policy.BaseDelay},
+		"max_delay":  {config.Retry.MaxDelay, &policy.MaxDelay},
+	} {
+		if setting.value.IsNull() {
+			continue
+		}
+
+		delay, err := time.ParseDuration(setting.value.ValueString())
+		if err != nil || delay < 0 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName(name),
+				"Invalid retry configuration",
+				fmt.Sprintf("%s must be a positive duration such as \"5s\", got %q", name, setting.value.ValueString()),
+			)
+			continue
+		}
+		*setting.target = delay
+	}
+
+	if policy.MaxDelay < policy.BaseDelay {
+		diags.AddAttributeError(
+			path.Root("retry").AtName("max_delay"),
+			"Invalid retry configuration",
+			"max_delay cannot be shorter than base_delay",
+		)
+	}
+
+	return policy, diags
+}
+
+// syncPolicy Resolves the sync policy from the "sync" block
+func (config providerData) syncPolicy() (SyncPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	policy := DefaultSyncPolicy
+
+	if config.Sync == nil {
+		return policy, diags
+	}
+
+	if config.Sync.Mode.IsUnknown() || config.Sync.MaxAge.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("sync"),
+			"Unable to create client",
+			"Cannot use unknown value in sync",
+		)
+		return policy, diags
+	}
+
+	policy.Mode = SyncMode(config.Sync.Mode.ValueString())
+	if !lo.Contains[SyncMode](SyncModes, policy.Mode) {
+		diags.AddAttributeError(
+			path.Root("sync").AtName("mode"),
+			"Invalid sync configuration",
+			fmt.Sprintf("mode must be one of %q, got %q", SyncModes, config.Sync.Mode.ValueString()),
+		)
+		return policy, diags
+	}
+
+	if config.Sync.MaxAge.IsNull() {
+		if policy.Mode == SyncIfOlderThan {
+			diags.AddAttributeError(
+				path.Root("sync").AtName("max_age"),
+				"Invalid sync configuration",
+				"max_age is required with the \"if_older_than\" mode",
+			)
+		}
+		return policy, diags
+	}
+
+	if policy.Mode != SyncIfOlderThan {
+		diags.AddAttributeError(
+			path.Root("sync").AtName("max_age"),
+			"Invalid sync configuration",
+			"max_age is only used with the \"if_older_than\" mode",
+		)
+		return policy, diags
+	}
+
+	maxAge, err := time.ParseDuration(config.Sync.MaxAge.ValueString())
+	if err != nil || maxAge <= 0 {
+		diags.AddAttributeError(
+			path.Root("sync").AtName("max_age"),
+			"Invalid sync configuration",
+			fmt.Sprintf("max_age must be a positive duration such as \"15m\", got %q", config.Sync.MaxAge.ValueString()),
+		)
+		return policy, diags
+	}
+	policy.MaxAge = maxAge
+
+	return policy, diags
 }
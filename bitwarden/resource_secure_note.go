@@ -2,11 +2,20 @@ package bitwarden
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math/big"
 
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -15,136 +24,350 @@ var providerErrorTitle = "Provider not configured"
 var providerErrorMessage = "The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!"
 
 func convertItemToState(item *Item, resource SecureNote) SecureNote {
-	var typedCollectionIDs []attr.Value
+	var result = SecureNote{
+		Object:         types.StringValue(item.Object),
+		ID:             types.StringValue(item.ID),
+		OrganizationId: types.StringValue(item.OrganizationId),
+		Type:           types.Int64Value(int64(item.Type)),
+		Name:           types.StringValue(item.Name),
+		Notes:          types.StringValue(item.Notes),
+		NotesWOVersion: resource.NotesWOVersion,
+		CollectionIDs:  item.CollectionIDs,
+		RevisionDate:   types.StringValue(item.RevisionDate),
+		Timeouts:       resource.Timeouts,
 
-	for _, v := range item.CollectionIDs {
-		typedCollectionIDs = append(typedCollectionIDs, types.String{Value: v})
+		DeletionProtection: resource.DeletionProtection,
 	}
 
-	var result = SecureNote{
-		Object:         types.String{Value: item.Object},
-		ID:             types.String{Value: item.ID},
-		OrganizationId: types.String{Value: item.OrganizationId},
-		Type:           types.Number{Value: big.NewFloat(float64(item.Type))},
-		Name:           types.String{Value: item.Name},
-		Notes:          types.String{Value: item.Notes},
-		CollectionIDs:  item.CollectionIDs,
-		RevisionDate:   types.String{Value: item.RevisionDate},
+	if item.OrganizationId == "" && resource.OrganizationId.IsNull() {
+		result.OrganizationId = types.StringNull()
 	}
 
-	if !resource.FolderID.Null {
-		result.FolderID = types.String{Value: item.FolderID}
-	} else {
-		result.FolderID = types.String{Null: true}
+	// bw returns no collections as an empty list, personal items are planned with null collections
+	if item.OrganizationId == "" {
+		result.CollectionIDs = nil
 	}
 
-	if !resource.Favorite.Null {
-		result.Favorite = types.Bool{Value: item.Favorite}
-	} else {
-		result.Favorite = types.Bool{Null: true}
+	// Notes written with notes_wo never reach the state
+	if resource.writeOnlyNotes() {
+		result.Notes = types.StringNull()
 	}
 
-	if !resource.Reprompt.Null {
-		result.Reprompt = types.Bool{Value: resource.Reprompt.Value}
+	if !resource.FolderID.IsNull() {
+		result.FolderID = types.StringValue(item.FolderID)
 	} else {
-		result.Reprompt = types.Bool{Null: true}
+		result.FolderID = types.StringNull()
 	}
+
+	result.Favorite = types.BoolValue(item.Favorite)
+	result.Reprompt = types.BoolValue(item.Reprompt != 0)
 	return result
 }
 
-type resourceSecureNoteType struct{}
+func newResourceSecureNote() resource.Resource {
+	return &resourceSecureNote{}
+}
+
+type resourceSecureNote struct {
+	p provider
+}
+
+func (r *resourceSecureNote) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_secure_note"
+}
 
-func (r resourceSecureNoteType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
-	return tfsdk.Schema{
-		Attributes: map[string]tfsdk.Attribute{
+func (r *resourceSecureNote) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// Bumped along with a state upgrader whenever the stored attributes change, see upgrade.go
+		Version: secureNoteSchemaVersion,
+		Attributes: map[string]schema.Attribute{
 			// Object type, generated by BitWarden
-			"object": {
-				Type:     types.StringType,
-				Computed: true,
+			"object": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			// Secure note ID, generated by BitWarden
-			"id": {
-				Type:     types.StringType,
-				Computed: true,
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			// Org ID this secure note belongs to, provided by the user
-			"organization_id": {
-				Type:     types.StringType,
-				Required: true,
+			// Org ID this secure note belongs to, provided by the user, defaults to default_organization_id, or to
+			// the personal vault without default. An empty string is the personal vault.
+			"organization_id": schema.StringAttribute{
+				Optional:   true,
+				Computed:   true,
+				Validators: []validator.String{uuidValidator{allowEmpty: true}},
 			},
-			// Folder ID where to store this secure note, provided by the user, defaults to null
-			"folder_id": {
-				Type:     types.StringType,
-				Optional: true,
+			// Folder ID where to store this secure note, provided by the user, defaults to default_folder_id or null
+			"folder_id": schema.StringAttribute{
+				Optional:   true,
+				Computed:   true,
+				Validators: []validator.String{uuidValidator{}},
 			},
 			// Object type, generated by BitWarden, for a secure note this value is always 2
-			"type": {
-				Type:     types.NumberType,
-				Computed: true,
+			"type": schema.Int64Attribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
 			},
-			// Requires a password prompt to open, provided by the user, default to false
-			"reprompt": {
-				Type:     types.BoolType,
+			// Requires a password prompt to open, provided by the user, defaults to false
+			"reprompt": schema.BoolAttribute{
 				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
 			},
 			// Secure note name, provided by the user
-			"name": {
-				Type:     types.StringType,
+			"name": schema.StringAttribute{
 				Required: true,
 			},
-			// Contents of the note, provided by the user
-			"notes": {
-				Type:     types.StringType,
-				Required: true,
+			// Contents of the note, provided by the user, either here or with notes_wo
+			"notes": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
 			},
-			// Mark as favorite, provided by the user, default to false
-			"favorite": {
-				Type:     types.BoolType,
-				Optional: true,
+			// Contents of the note, provided by the user, sent to BitWarden without being stored in the state or the
+			// plan. Requires Terraform 1.11.
+			"notes_wo": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
 			},
-			// Collections where this secure note should be, provided by the user
-			"collection_ids": {
-				Type:          types.ListType{ElemType: types.StringType},
-				Required:      true,
-				PlanModifiers: tfsdk.AttributePlanModifiers{tfsdk.RequiresReplaceModifier{}},
+			// Version of notes_wo, provided by the user, the notes are only written when it changes
+			"notes_wo_version": schema.Int64Attribute{
+				Optional: true,
 			},
-			// Last update date, generated by BitWarden
-			"revision_date": {
-				Type:     types.StringType,
+			// Mark as favorite, provided by the user, defaults to false
+			"favorite": schema.BoolAttribute{
+				Optional: true,
 				Computed: true,
+				Default:  booldefault.StaticBool(false),
 			},
+			// Collections where this secure note should be, provided by the user, defaults to default_collection_ids.
+			// Updated in place, in whatever order BitWarden returns them.
+			"collection_ids": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Validators:  []validator.Set{uuidValidator{}},
+			},
+			// Last update date, generated by BitWarden, only planned to change along with the secure note
+			"revision_date": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			// Makes deleting the secure note fail, provided by the user, defaults to false
+			"deletion_protection": schema.BoolAttribute{
+				Optional: true,
+			},
+			// Time allowed for each operation, provided by the user, defaults to 10 minutes
+			"timeouts": timeouts.AttributesAll(ctx),
 		},
-	}, nil
+	}
 }
 
-func (r resourceSecureNoteType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
-	return resourceSecureNote{
-		p: *(p.(*provider)),
-	}, nil
+func (r *resourceSecureNote) IdentitySchema(
+	_ context.Context,
+	_ resource.IdentitySchemaRequest,
+	resp *resource.IdentitySchemaResponse,
+) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+			},
+		},
+	}
 }
 
-type resourceSecureNote struct {
-	p provider
+// setSecureNoteIdentity Records the ID of the secure note as its resource identity, Terraform before 1.12 has none
+func setSecureNoteIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, id string) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+
+	return identity.Set(ctx, SecureNoteIdentity{ID: types.StringValue(id)})
 }
 
-func (r resourceSecureNote) ImportState(
+func (r *resourceSecureNote) Configure(
 	_ context.Context,
-	_ tfsdk.ImportResourceStateRequest,
-	_ *tfsdk.ImportResourceStateResponse,
+	req resource.ConfigureRequest,
+	_ *resource.ConfigureResponse,
+) {
+	// Left unconfigured until the provider is, the operations report it
+	if p, ok := req.ProviderData.(*provider); ok {
+		r.p = *p
+	}
+}
+
+func (r *resourceSecureNote) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		conflictingAttributesValidator{attributes: []string{"notes", "notes_wo"}, required: true},
+	}
+}
+
+// ValidateConfig Makes sure notes_wo comes with the notes_wo_version that triggers writing it
+func (r *resourceSecureNote) ValidateConfig(
+	ctx context.Context,
+	req resource.ValidateConfigRequest,
+	resp *resource.ValidateConfigResponse,
+) {
+	var config SecureNote
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.NotesWO.IsNull() != config.NotesWOVersion.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("notes_wo_version"),
+			"Incomplete resource configuration",
+			"notes_wo and notes_wo_version must be set together, the notes are written again when "+
+				"notes_wo_version changes",
+		)
+	}
+}
+
+// ModifyPlan Shows the organization, collections and folder the provider defaults resolve to, checks them against
+// the vault, and rejects the changes a read-only provider or the deletion protection forbid
+func (r *resourceSecureNote) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
 ) {
-	// Implement this at some point
+	if !r.p.configured {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		var state SecureNote
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resource := fmt.Sprintf("secure note %s", state.ID.ValueString())
+		if checkDeletionProtection(&resp.Diagnostics, state.DeletionProtection, resource) {
+			r.p.checkWritable(&resp.Diagnostics, "delete", resource)
+		}
+		return
+	}
+
+	resp.Diagnostics.Append(r.p.defaults.applyItemDefaults(ctx, req.Config, &resp.Plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.p.validateItemOwnership(ctx, resp.Plan, req.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(planItemMove(ctx, resp.Plan, req.State, "secure note", &resp.RequiresReplace)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(planRevisionDate(ctx, &resp.Plan, req.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		r.p.checkWritable(&resp.Diagnostics, "create", "secure notes")
+	} else if !resp.Plan.Raw.Equal(req.State.Raw) {
+		r.p.checkWritable(&resp.Diagnostics, "update", "secure notes")
+	}
+}
+
+// ImportState Adopts an existing secure note, by ID or by <organization_id>/<collection_id>/<name>, or by the
+// resource identity Terraform 1.12+ import blocks and terraform query give
+func (r *resourceSecureNote) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	if !r.p.configured {
+		resp.Diagnostics.AddError(providerErrorTitle, providerErrorMessage)
+		return
+	}
+
+	importId := req.ID
+	if importId == "" && req.Identity != nil {
+		var identity SecureNoteIdentity
+		diags := req.Identity.Get(ctx, &identity)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		importId = identity.ID.ValueString()
+	}
+
+	id, err := parseImportID(importId)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	secureNote, err := findItem(ctx, r.p.client, id, secureNoteItemType)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing secure note",
+			fmt.Sprintf("Could not import secure note %s: %s", importId, err.Error()),
+		)
+		return
+	}
+
+	diags := resp.State.Set(ctx, importedSecureNote(ctx, secureNote))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = setSecureNoteIdentity(ctx, resp.Identity, secureNote.ID)
+	resp.Diagnostics.Append(diags...)
+}
+
+// importedSecureNote State of an imported secure note, the optional attributes left to their default are null so
+// that a configuration omitting them, like the one Terraform generates, plans no change. favorite and reprompt
+// default to false, so they always hold the values of the item.
+func importedSecureNote(ctx context.Context, item *Item) SecureNote {
+	resource := SecureNote{
+		OrganizationId:     types.StringNull(),
+		FolderID:           types.StringNull(),
+		NotesWOVersion:     types.Int64Null(),
+		DeletionProtection: types.BoolNull(),
+		Timeouts:           nullTimeouts(ctx),
+	}
+	if item.OrganizationId != "" {
+		resource.OrganizationId = types.StringValue(item.OrganizationId)
+	}
+	if item.FolderID != "" {
+		resource.FolderID = types.StringValue(item.FolderID)
+	}
+
+	return convertItemToState(item, resource)
 }
 
-func (r resourceSecureNote) Create(
+func (r *resourceSecureNote) Create(
 	ctx context.Context,
-	req tfsdk.CreateResourceRequest,
-	resp *tfsdk.CreateResourceResponse,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
 ) {
 	if !r.p.configured {
 		resp.Diagnostics.AddError(providerErrorTitle, providerErrorMessage)
 		return
 	}
 
+	if !r.p.checkWritable(&resp.Diagnostics, "create", "secure notes") {
+		return
+	}
+
 	// Retrieve values from plan
 	var plan SecureNote
 	diags := req.Plan.Get(ctx, &plan)
@@ -153,11 +376,25 @@ func (r resourceSecureNote) Create(
 		return
 	}
 
-	secureNote, err := r.p.client.CreateSecureNote(plan)
+	// Write-only notes are only in the configuration
+	diags = req.Config.GetAttribute(ctx, path.Root("notes_wo"), &plan.NotesWO)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withTimeout(ctx, plan.Timeouts.Create)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secureNote, err := r.p.client.CreateItem(ctx, PrepareSecureNoteCreate(plan))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating secure note",
-			fmt.Sprintf("Could not create secure note with plan %#v\n error: %s", plan, err.Error()),
+			fmt.Sprintf("Could not create secure note %q: %s", plan.Name.ValueString(), err.Error()),
 		)
 		return
 	}
@@ -169,9 +406,12 @@ func (r resourceSecureNote) Create(
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	diags = setSecureNoteIdentity(ctx, resp.Identity, secureNote.ID)
+	resp.Diagnostics.Append(diags...)
 }
 
-func (r resourceSecureNote) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+func (r *resourceSecureNote) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	if !r.p.configured {
 		resp.Diagnostics.AddError(providerErrorTitle, providerErrorMessage)
 		return
@@ -184,9 +424,21 @@ func (r resourceSecureNote) Read(ctx context.Context, req tfsdk.ReadResourceRequ
 		return
 	}
 
-	secureNoteId := state.ID.Value
+	secureNoteId := state.ID.ValueString()
 
-	secureNote, err := r.p.client.GetItem(secureNoteId)
+	ctx, cancel, diags := withTimeout(ctx, state.Timeouts.Read)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secureNote, err := r.p.client.GetItem(ctx, secureNoteId)
+	if errors.Is(err, ErrItemNotFound) {
+		// Deleted outside of Terraform, forget about it so that it gets re-created
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading secure note",
@@ -195,6 +447,28 @@ func (r resourceSecureNote) Read(ctx context.Context, req tfsdk.ReadResourceRequ
 		return
 	}
 
+	if secureNote.InTrash() {
+		// Restoring is a change a read-only provider doesn't make
+		if !r.p.restoreTrashedItems || r.p.readOnly {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		secureNote, err = r.restoreSecureNote(ctx, secureNoteId)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error restoring secure note",
+				fmt.Sprintf("Could not restore secure note ID %s from the trash: %s", secureNoteId, err.Error()),
+			)
+			return
+		}
+
+		resp.Diagnostics.AddWarning(
+			"Secure note restored from trash",
+			fmt.Sprintf("Secure note ID %s was in the trash and has been restored", secureNoteId),
+		)
+	}
+
 	newState := convertItemToState(secureNote, state)
 
 	diags = resp.State.Set(ctx, &newState)
@@ -202,12 +476,16 @@ func (r resourceSecureNote) Read(ctx context.Context, req tfsdk.ReadResourceRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// States written before resource identities have none yet
+	diags = setSecureNoteIdentity(ctx, resp.Identity, secureNote.ID)
+	resp.Diagnostics.Append(diags...)
 }
 
-func (r resourceSecureNote) Update(
+func (r *resourceSecureNote) Update(
 	ctx context.Context,
-	req tfsdk.UpdateResourceRequest,
-	resp *tfsdk.UpdateResourceResponse,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
 ) {
 	if !r.p.configured {
 		resp.Diagnostics.AddError(providerErrorTitle, providerErrorMessage)
@@ -228,25 +506,79 @@ func (r resourceSecureNote) Update(
 		return
 	}
 
-	secureNoteId := state.ID.Value
+	secureNoteId := state.ID.ValueString()
+
+	if !r.p.checkWritable(&resp.Diagnostics, "update", fmt.Sprintf("secure note %s", secureNoteId)) {
+		return
+	}
+
+	changed, diags := itemChanged(ctx, req.Plan, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only attributes known to Terraform changed, the secure note and its revision date stay as they are
+	if !changed {
+		plan.Object, plan.ID, plan.Type, plan.RevisionDate = state.Object, state.ID, state.Type, state.RevisionDate
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
 
-	if plan.OrganizationId.Value != state.OrganizationId.Value {
-		err := r.p.client.MoveItem(secureNoteId, plan.OrganizationId.Value)
+	ctx, cancel, diags := withTimeout(ctx, plan.Timeouts.Update)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only notes are only written along with a new notes_wo_version, otherwise the secure note keeps its notes
+	if plan.writeOnlyNotes() && plan.NotesWOVersion.Equal(state.NotesWOVersion) {
+		current, err := r.p.client.GetItem(ctx, secureNoteId)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating secure note",
+				fmt.Sprintf("Could not read the notes of secure note ID %s: %s", secureNoteId, err.Error()),
+			)
+			return
+		}
+		plan.NotesWO = types.StringValue(current.Notes)
+	} else if plan.writeOnlyNotes() {
+		diags = req.Config.GetAttribute(ctx, path.Root("notes_wo"), &plan.NotesWO)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Leaving an organization is planned as a replacement, only personal items get here with a new organization
+	if plan.OrganizationId.ValueString() != state.OrganizationId.ValueString() {
+		err := r.p.client.MoveItem(ctx, secureNoteId, plan.OrganizationId.ValueString(), plan.CollectionIDs)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error updating secure note",
 				fmt.Sprintf(
 					"Could not move secure note ID %s to Org %s: %s",
 					secureNoteId,
-					plan.OrganizationId.Value,
+					plan.OrganizationId.ValueString(),
 					err.Error(),
 				),
 			)
 			return
 		}
+	} else if plan.OrganizationId.ValueString() != "" && !sameElements(plan.CollectionIDs, state.CollectionIDs) {
+		_, err := r.p.client.UpdateItemCollections(ctx, secureNoteId, plan.CollectionIDs)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating secure note",
+				fmt.Sprintf("Could not update the collections of secure note ID %s: %s", secureNoteId, err.Error()),
+			)
+			return
+		}
 	}
 
-	secureNote, err := r.p.client.UpdateSecureNote(secureNoteId, plan)
+	secureNote, err := r.p.client.UpdateItem(ctx, secureNoteId, PrepareSecureNoteCreate(plan))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating secure note",
@@ -264,10 +596,10 @@ func (r resourceSecureNote) Update(
 	}
 }
 
-func (r resourceSecureNote) Delete(
+func (r *resourceSecureNote) Delete(
 	ctx context.Context,
-	req tfsdk.DeleteResourceRequest,
-	resp *tfsdk.DeleteResourceResponse,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
 ) {
 	if !r.p.configured {
 		resp.Diagnostics.AddError(providerErrorTitle, providerErrorMessage)
@@ -281,10 +613,23 @@ func (r resourceSecureNote) Delete(
 		return
 	}
 
-	secureNoteId := state.ID.Value
+	secureNoteId := state.ID.ValueString()
 
-	err := r.p.client.DeleteItem(secureNoteId)
-	if err != nil {
+	resource := fmt.Sprintf("secure note %s", secureNoteId)
+	if !checkDeletionProtection(&resp.Diagnostics, state.DeletionProtection, resource) ||
+		!r.p.checkWritable(&resp.Diagnostics, "delete", resource) {
+		return
+	}
+
+	ctx, cancel, diags := withTimeout(ctx, state.Timeouts.Delete)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.p.client.DeleteItem(ctx, secureNoteId)
+	if err != nil && !errors.Is(err, ErrItemNotFound) {
 		resp.Diagnostics.AddError(
 			"Error deleting secure note",
 			fmt.Sprintf("Could not delete secure note with ID %s: %s", secureNoteId, err.Error()),
@@ -294,3 +639,12 @@ func (r resourceSecureNote) Delete(
 
 	resp.State.RemoveResource(ctx)
 }
+
+func (r *resourceSecureNote) restoreSecureNote(ctx context.Context, id string) (*Item, error) {
+	err := r.p.client.RestoreItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.p.client.GetItem(ctx, id)
+}
@@ -0,0 +1,225 @@
+package bitwarden_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"terraform-bitwarden-sync/bitwarden"
+	"terraform-bitwarden-sync/internal/bwtest"
+)
+
+var protoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"bitwarden": providerserver.NewProtocol6WithError(bitwarden.New()),
+}
+
+func secureNoteConfig(organizationId string, collectionId string, notes string) string {
+	return fmt.Sprintf(`
+provider "bitwarden" {}
+
+resource "bitwarden_secure_note" "test" {
+  organization_id = %q
+  collection_ids  = [%q]
+  name            = "Acceptance test note"
+  notes           = %q
+}
+`, organizationId, collectionId, notes)
+}
+
+// checkSecureNoteInVault Checks the note in the state matches the one in the fake vault
+func checkSecureNoteInVault(env *bwtest.Environment, notes string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["bitwarden_secure_note.test"]
+		if !ok {
+			return fmt.Errorf("bitwarden_secure_note.test not found in state")
+		}
+
+		item, err := env.Vault.GetItem(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if item.Notes != notes {
+			return fmt.Errorf("expected notes %q in the vault, got %q", notes, item.Notes)
+		}
+
+		return nil
+	}
+}
+
+func checkSecureNotesTrashed(env *bwtest.Environment) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		for _, item := range env.Vault.Items() {
+			if !item.InTrash() {
+				return fmt.Errorf("item %s was not deleted", item.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestAccSecureNote_basic(t *testing.T) {
+	env := bwtest.NewEnvironment(t)
+	org := env.Vault.AddOrganization("Acceptance")
+	collection := env.Vault.AddCollection(org.ID, "Acceptance")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		CheckDestroy:             checkSecureNotesTrashed(env),
+		Steps: []resource.TestStep{
+			{
+				Config: secureNoteConfig(org.ID, collection.ID, "first secret"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("bitwarden_secure_note.test", "id"),
+					resource.TestCheckResourceAttr("bitwarden_secure_note.test", "organization_id", org.ID),
+					resource.TestCheckResourceAttr("bitwarden_secure_note.test", "notes", "first secret"),
+					resource.TestCheckResourceAttr("bitwarden_secure_note.test", "type", "2"),
+					checkSecureNoteInVault(env, "first secret"),
+				),
+			},
+			{
+				Config: secureNoteConfig(org.ID, collection.ID, "second secret"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden_secure_note.test", "notes", "second secret"),
+					checkSecureNoteInVault(env, "second secret"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSecureNote_personal(t *testing.T) {
+	env := bwtest.NewEnvironment(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		CheckDestroy:             checkSecureNotesTrashed(env),
+		Steps: []resource.TestStep{
+			{
+				// The plan after the apply must be empty, with collection_ids null in the state too
+				Config: `
+provider "bitwarden" {}
+
+resource "bitwarden_secure_note" "test" {
+  name  = "Acceptance test note"
+  notes = "personal secret"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("bitwarden_secure_note.test", "organization_id"),
+					resource.TestCheckNoResourceAttr("bitwarden_secure_note.test", "collection_ids.#"),
+					checkSecureNoteInVault(env, "personal secret"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSecureNote_deletedOutsideTerraform(t *testing.T) {
+	env := bwtest.NewEnvironment(t)
+	org := env.Vault.AddOrganization("Acceptance")
+	collection := env.Vault.AddCollection(org.ID, "Acceptance")
+
+	var firstId string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		CheckDestroy:             checkSecureNotesTrashed(env),
+		Steps: []resource.TestStep{
+			{
+				Config: secureNoteConfig(org.ID, collection.ID, "secret"),
+				Check: func(s *terraform.State) error {
+					firstId = s.RootModule().Resources["bitwarden_secure_note.test"].Primary.ID
+					return nil
+				},
+			},
+			{
+				// Someone deleted the note from the web vault, it must be re-created
+				PreConfig: func() { env.Vault.TrashItem(firstId) },
+				Config:    secureNoteConfig(org.ID, collection.ID, "secret"),
+				Check: func(s *terraform.State) error {
+					if s.RootModule().Resources["bitwarden_secure_note.test"].Primary.ID == firstId {
+						return fmt.Errorf("expected the trashed secure note to be re-created")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccSecureNote_cliTransport(t *testing.T) {
+	env := bwtest.NewEnvironment(t)
+	env.UseCLITransport(t)
+	org := env.Vault.AddOrganization("Acceptance")
+	collection := env.Vault.AddCollection(org.ID, "Acceptance")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		CheckDestroy:             checkSecureNotesTrashed(env),
+		Steps: []resource.TestStep{
+			{
+				Config: secureNoteConfig(org.ID, collection.ID, "secret"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("bitwarden_secure_note.test", "id"),
+					checkSecureNoteInVault(env, "secret"),
+				),
+			},
+		},
+	})
+}
+
+func secureNoteWriteOnlyConfig(organizationId string, collectionId string, notes string, version int) string {
+	return fmt.Sprintf(`
+provider "bitwarden" {}
+
+resource "bitwarden_secure_note" "test" {
+  organization_id  = %q
+  collection_ids   = [%q]
+  name             = "Acceptance test note"
+  notes_wo         = %q
+  notes_wo_version = %d
+}
+`, organizationId, collectionId, notes, version)
+}
+
+func TestAccSecureNote_writeOnlyNotes(t *testing.T) {
+	env := bwtest.NewEnvironment(t)
+	org := env.Vault.AddOrganization("Acceptance")
+	collection := env.Vault.AddCollection(org.ID, "Acceptance")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		TerraformVersionChecks:   []tfversion.TerraformVersionCheck{tfversion.SkipBelow(tfversion.Version1_11_0)},
+		CheckDestroy:             checkSecureNotesTrashed(env),
+		Steps: []resource.TestStep{
+			{
+				Config: secureNoteWriteOnlyConfig(org.ID, collection.ID, "first secret", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("bitwarden_secure_note.test", "notes"),
+					resource.TestCheckNoResourceAttr("bitwarden_secure_note.test", "notes_wo"),
+					checkSecureNoteInVault(env, "first secret"),
+				),
+			},
+			{
+				// The notes are only written along with a new version
+				Config: secureNoteWriteOnlyConfig(org.ID, collection.ID, "ignored secret", 1),
+				Check:  checkSecureNoteInVault(env, "first secret"),
+			},
+			{
+				Config: secureNoteWriteOnlyConfig(org.ID, collection.ID, "second secret", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden_secure_note.test", "notes_wo_version", "2"),
+					checkSecureNoteInVault(env, "second secret"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,969 @@
+package bitwarden
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type secureNoteFixture struct {
+	resource   resourceSecureNote
+	vault      *FakeVault
+	schema     schema.Schema
+	org        Organization
+	collection Collection
+}
+
+func newSecureNoteFixture(t *testing.T) *secureNoteFixture {
+	t.Helper()
+
+	vault := NewFakeVault()
+	org := vault.AddOrganization("Org")
+	collection := vault.AddCollection(org.ID, "Collection")
+
+	schemaResp := resource.SchemaResponse{}
+	(&resourceSecureNote{}).Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	return &secureNoteFixture{
+		resource:   resourceSecureNote{p: provider{configured: true, client: vault}},
+		vault:      vault,
+		schema:     schemaResp.Schema,
+		org:        org,
+		collection: collection,
+	}
+}
+
+// plannedNote Secure note as Terraform plans it on creation, with computed attributes unknown
+func (f *secureNoteFixture) plannedNote(name string, notes string) SecureNote {
+	return SecureNote{
+		Object:         types.StringUnknown(),
+		ID:             types.StringUnknown(),
+		OrganizationId: types.StringValue(f.org.ID),
+		FolderID:       types.StringNull(),
+		Type:           types.Int64Unknown(),
+		Reprompt:       types.BoolValue(false),
+		Name:           types.StringValue(name),
+		Notes:          types.StringValue(notes),
+		Favorite:       types.BoolValue(false),
+		CollectionIDs:  []string{f.collection.ID},
+		RevisionDate:   types.StringUnknown(),
+
+		DeletionProtection: types.BoolNull(),
+		Timeouts:           nullTimeouts(context.Background()),
+	}
+}
+
+func (f *secureNoteFixture) plan(t *testing.T, note SecureNote) tfsdk.Plan {
+	t.Helper()
+
+	state := f.state(t, note)
+	return tfsdk.Plan{Raw: state.Raw, Schema: f.schema}
+}
+
+// config Configuration matching a planned secure note, along with the write-only notes Terraform only sends there
+func (f *secureNoteFixture) config(t *testing.T, note SecureNote, notesWO string) tfsdk.Config {
+	t.Helper()
+
+	if notesWO != "" {
+		note.NotesWO = types.StringValue(notesWO)
+	}
+	state := f.state(t, note)
+	return tfsdk.Config{Raw: state.Raw, Schema: f.schema}
+}
+
+func (f *secureNoteFixture) state(t *testing.T, note SecureNote) tfsdk.State {
+	t.Helper()
+
+	state := tfsdk.State{Schema: f.schema}
+	if diags := state.Set(context.Background(), note); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	return state
+}
+
+func (f *secureNoteFixture) create(t *testing.T, note SecureNote) tfsdk.State {
+	t.Helper()
+
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: f.schema}}
+	f.resource.Create(
+		context.Background(),
+		resource.CreateRequest{Config: f.config(t, note, ""), Plan: f.plan(t, note)},
+		&resp,
+	)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	return resp.State
+}
+
+func (f *secureNoteFixture) read(t *testing.T, state tfsdk.State) resource.ReadResponse {
+	t.Helper()
+
+	resp := resource.ReadResponse{State: state}
+	f.resource.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+func getSecureNote(t *testing.T, state tfsdk.State) SecureNote {
+	t.Helper()
+
+	var note SecureNote
+	if diags := state.Get(context.Background(), &note); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	return note
+}
+
+func TestSecureNoteCreateAndRead(t *testing.T) {
+	f := newSecureNoteFixture(t)
+
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	created := getSecureNote(t, state)
+
+	if created.ID.IsUnknown() || created.ID.ValueString() == "" {
+		t.Fatal("expected the ID to be set after create")
+	}
+	if created.Notes.ValueString() != "secret" {
+		t.Errorf("expected notes %q, got %q", "secret", created.Notes.ValueString())
+	}
+
+	read := getSecureNote(t, f.read(t, state).State)
+	if read.RevisionDate.ValueString() != created.RevisionDate.ValueString() {
+		t.Errorf("expected revision date %q, got %q", created.RevisionDate.ValueString(), read.RevisionDate.ValueString())
+	}
+}
+
+func TestSecureNoteUpdate(t *testing.T) {
+	f := newSecureNoteFixture(t)
+
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	planned := getSecureNote(t, state)
+	planned.Notes = types.StringValue("new secret")
+	planned.RevisionDate = types.StringUnknown()
+
+	resp := resource.UpdateResponse{State: state}
+	f.resource.Update(
+		context.Background(),
+		resource.UpdateRequest{State: state, Plan: f.plan(t, planned)},
+		&resp,
+	)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	updated := getSecureNote(t, resp.State)
+	if updated.Notes.ValueString() != "new secret" {
+		t.Errorf("expected notes %q, got %q", "new secret", updated.Notes.ValueString())
+	}
+	if updated.ID.ValueString() != planned.ID.ValueString() {
+		t.Errorf("expected the ID to stay %s, got %s", planned.ID.ValueString(), updated.ID.ValueString())
+	}
+}
+
+func TestSecureNoteWriteOnlyNotes(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	ctx := context.Background()
+
+	planned := f.plannedNote("Note", "")
+	planned.Notes = types.StringNull()
+	planned.NotesWOVersion = types.Int64Value(1)
+
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: f.schema}}
+	f.resource.Create(
+		ctx,
+		resource.CreateRequest{Config: f.config(t, planned, "secret"), Plan: f.plan(t, planned)},
+		&resp,
+	)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	state := resp.State
+	created := getSecureNote(t, state)
+	if !created.Notes.IsNull() || !created.NotesWO.IsNull() {
+		t.Errorf("expected the notes to stay out of the state, got %#v", created)
+	}
+	if item, _ := f.vault.GetItem(ctx, created.ID.ValueString()); item.Notes != "secret" {
+		t.Errorf("expected notes %q in the vault, got %q", "secret", item.Notes)
+	}
+	if read := getSecureNote(t, f.read(t, state).State); !read.Notes.IsNull() {
+		t.Errorf("expected the refresh to keep the notes out of the state, got %#v", read.Notes)
+	}
+
+	update := func(planned SecureNote, notesWO string) tfsdk.State {
+		t.Helper()
+
+		planned = f.modifyUpdatePlan(t, state, planned)
+		resp := resource.UpdateResponse{State: state}
+		f.resource.Update(
+			ctx,
+			resource.UpdateRequest{Config: f.config(t, planned, notesWO), Plan: f.plan(t, planned), State: state},
+			&resp,
+		)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		return resp.State
+	}
+
+	// Without a new version, the notes in the configuration are not written
+	renamed := created
+	renamed.Name = types.StringValue("Renamed")
+	state = update(renamed, "ignored")
+	item, _ := f.vault.GetItem(ctx, created.ID.ValueString())
+	if item.Name != "Renamed" || item.Notes != "secret" {
+		t.Errorf("expected only the name to change, got %#v", item)
+	}
+
+	rotated := getSecureNote(t, state)
+	rotated.NotesWOVersion = types.Int64Value(2)
+	state = update(rotated, "rotated")
+	if item, _ := f.vault.GetItem(ctx, created.ID.ValueString()); item.Notes != "rotated" {
+		t.Errorf("expected notes %q in the vault, got %q", "rotated", item.Notes)
+	}
+	if updated := getSecureNote(t, state); !updated.Notes.IsNull() || updated.NotesWOVersion.ValueInt64() != 2 {
+		t.Errorf("expected the new version without the notes in state, got %#v", updated)
+	}
+}
+
+func TestSecureNoteNotesConfiguration(t *testing.T) {
+	f := newSecureNoteFixture(t)
+
+	tests := map[string]struct {
+		notes   types.String
+		notesWO string
+		version types.Int64
+		valid   bool
+	}{
+		"notes":            {notes: types.StringValue("secret"), version: types.Int64Null(), valid: true},
+		"write-only notes": {notes: types.StringNull(), notesWO: "secret", version: types.Int64Value(1), valid: true},
+		"both":             {notes: types.StringValue("secret"), notesWO: "secret", version: types.Int64Value(1)},
+		"none":             {notes: types.StringNull(), version: types.Int64Null()},
+		"without version":  {notes: types.StringNull(), notesWO: "secret", version: types.Int64Null()},
+		"version only":     {notes: types.StringValue("secret"), version: types.Int64Value(1)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			note := f.plannedNote("Note", "")
+			note.Notes, note.NotesWOVersion = test.notes, test.version
+			req := resource.ValidateConfigRequest{Config: f.config(t, note, test.notesWO)}
+
+			resp := resource.ValidateConfigResponse{}
+			for _, v := range f.resource.ConfigValidators(context.Background()) {
+				v.ValidateResource(context.Background(), req, &resp)
+			}
+			f.resource.ValidateConfig(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() == test.valid {
+				t.Errorf("expected valid=%t, got %v", test.valid, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestSecureNoteUpdatesCollectionsInPlace(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	other := f.vault.AddCollection(f.org.ID, "Other")
+
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	created := getSecureNote(t, state)
+
+	planned := created
+	planned.CollectionIDs = []string{other.ID, f.collection.ID}
+	planned = f.modifyUpdatePlan(t, state, planned)
+	if !planned.RevisionDate.IsUnknown() {
+		t.Errorf("expected the revision date to be unknown, got %#v", planned.RevisionDate)
+	}
+
+	resp := resource.UpdateResponse{State: state}
+	f.resource.Update(
+		context.Background(),
+		resource.UpdateRequest{State: state, Plan: f.plan(t, planned)},
+		&resp,
+	)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	item, err := f.vault.GetItem(context.Background(), created.ID.ValueString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameElements(item.CollectionIDs, []string{f.collection.ID, other.ID}) {
+		t.Errorf("expected the secure note to be in both collections, got %v", item.CollectionIDs)
+	}
+	if updated := getSecureNote(t, resp.State); updated.ID.ValueString() != created.ID.ValueString() {
+		t.Errorf("expected the ID to stay %s, got %s", created.ID.ValueString(), updated.ID.ValueString())
+	}
+
+	// The order of the collections is not a change
+	reordered := getSecureNote(t, resp.State)
+	reordered.CollectionIDs = []string{f.collection.ID, other.ID}
+	if result := f.modifyUpdatePlan(t, resp.State, reordered); result.RevisionDate.IsUnknown() {
+		t.Error("expected reordering the collections to plan no change")
+	}
+}
+
+// modifyUpdatePlan Plans an update the way Terraform does once the attribute plan modifiers kept the computed
+// attributes of the prior state
+func (f *secureNoteFixture) modifyUpdatePlan(t *testing.T, state tfsdk.State, planned SecureNote) SecureNote {
+	t.Helper()
+
+	resp := f.modifyUpdatePlanResponse(t, state, planned)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var result SecureNote
+	if diags := resp.Plan.Get(context.Background(), &result); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	return result
+}
+
+func (f *secureNoteFixture) modifyUpdatePlanResponse(
+	t *testing.T,
+	state tfsdk.State,
+	planned SecureNote,
+) resource.ModifyPlanResponse {
+	t.Helper()
+
+	plan := f.plan(t, planned)
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	f.resource.ModifyPlan(
+		context.Background(),
+		resource.ModifyPlanRequest{Config: tfsdk.Config{Raw: plan.Raw, Schema: f.schema}, Plan: plan, State: state},
+		&resp,
+	)
+	return resp
+}
+
+func TestSecureNotePlanKeepsRevisionDateWithoutChanges(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	created := getSecureNote(t, state)
+
+	planned := created
+	planned.DeletionProtection = types.BoolValue(true)
+	if result := f.modifyUpdatePlan(t, state, planned); result.RevisionDate.ValueString() != created.RevisionDate.ValueString() {
+		t.Errorf("expected the revision date to be kept, got %#v", result.RevisionDate)
+	}
+
+	resp := resource.UpdateResponse{State: state}
+	f.resource.Update(
+		context.Background(),
+		resource.UpdateRequest{State: state, Plan: f.plan(t, planned)},
+		&resp,
+	)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	updated := getSecureNote(t, resp.State)
+	if updated.RevisionDate.ValueString() != created.RevisionDate.ValueString() || !updated.DeletionProtection.ValueBool() {
+		t.Errorf("expected only deletion_protection to change, got %#v", updated)
+	}
+	if item, _ := f.vault.GetItem(context.Background(), created.ID.ValueString()); item.RevisionDate != created.RevisionDate.ValueString() {
+		t.Errorf("expected the secure note to be left untouched in BitWarden, got revision %s", item.RevisionDate)
+	}
+}
+
+func TestSecureNotePlanMarksRevisionDateOnChanges(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	created := getSecureNote(t, state)
+
+	planned := created
+	planned.Notes = types.StringUnknown()
+	result := f.modifyUpdatePlan(t, state, planned)
+	if !result.RevisionDate.IsUnknown() {
+		t.Errorf("expected the revision date to be unknown, got %#v", result.RevisionDate)
+	}
+	if result.ID.ValueString() != created.ID.ValueString() {
+		t.Errorf("expected the ID to stay %s, got %#v", created.ID.ValueString(), result.ID)
+	}
+}
+
+func TestSecureNoteReadRemovesDeletedItems(t *testing.T) {
+	for name, remove := range map[string]func(*FakeVault, string){
+		"trashed": (*FakeVault).TrashItem,
+		"purged":  (*FakeVault).PurgeItem,
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := newSecureNoteFixture(t)
+
+			state := f.create(t, f.plannedNote("Note", "secret"))
+			remove(f.vault, getSecureNote(t, state).ID.ValueString())
+
+			resp := f.read(t, state)
+			if !resp.State.Raw.IsNull() {
+				t.Error("expected the secure note to be removed from state")
+			}
+		})
+	}
+}
+
+func TestSecureNoteReadRestoresTrashedItems(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	f.resource.p.restoreTrashedItems = true
+
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	id := getSecureNote(t, state).ID.ValueString()
+	f.vault.TrashItem(id)
+
+	resp := f.read(t, state)
+	if resp.State.Raw.IsNull() {
+		t.Fatal("expected the secure note to stay in state")
+	}
+
+	item, err := f.vault.GetItem(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.InTrash() {
+		t.Error("expected the secure note to be restored")
+	}
+}
+
+func TestSecureNoteDelete(t *testing.T) {
+	f := newSecureNoteFixture(t)
+
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	id := getSecureNote(t, state).ID.ValueString()
+
+	resp := resource.DeleteResponse{State: state}
+	f.resource.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if !resp.State.Raw.IsNull() {
+		t.Error("expected the secure note to be removed from state")
+	}
+
+	item, err := f.vault.GetItem(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !item.InTrash() {
+		t.Error("expected the secure note to be in the trash")
+	}
+}
+
+func (f *secureNoteFixture) modifyPlan(t *testing.T, config SecureNote) resource.ModifyPlanResponse {
+	t.Helper()
+
+	plan := f.plan(t, config)
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	f.resource.ModifyPlan(
+		context.Background(),
+		resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Raw: plan.Raw, Schema: f.schema},
+			Plan:   plan,
+			State:  tfsdk.State{Raw: tftypes.NewValue(f.schema.Type().TerraformType(context.Background()), nil), Schema: f.schema},
+		},
+		&resp,
+	)
+	return resp
+}
+
+func TestSecureNotePlanUsesProviderDefaults(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	folder := f.vault.AddFolder("Folder")
+	f.resource.p.defaults = itemDefaults{
+		OrganizationID: f.org.ID,
+		CollectionIDs:  []string{f.collection.ID},
+		FolderID:       folder.ID,
+	}
+
+	config := f.plannedNote("Note", "secret")
+	config.OrganizationId = types.StringNull()
+	config.CollectionIDs = nil
+
+	resp := f.modifyPlan(t, config)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var planned SecureNote
+	if diags := resp.Plan.Get(context.Background(), &planned); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if planned.OrganizationId.ValueString() != f.org.ID {
+		t.Errorf("expected the default organization %s, got %s", f.org.ID, planned.OrganizationId.ValueString())
+	}
+	if len(planned.CollectionIDs) != 1 || planned.CollectionIDs[0] != f.collection.ID {
+		t.Errorf("expected the default collections, got %v", planned.CollectionIDs)
+	}
+	if planned.FolderID.ValueString() != folder.ID {
+		t.Errorf("expected the default folder %s, got %s", folder.ID, planned.FolderID.ValueString())
+	}
+
+	state := f.create(t, planned)
+	if created := getSecureNote(t, state); created.FolderID.ValueString() != folder.ID {
+		t.Errorf("expected the secure note to be created in the default folder, got %q", created.FolderID.ValueString())
+	}
+}
+
+func TestSecureNotePlanPersonalItem(t *testing.T) {
+	f := newSecureNoteFixture(t)
+
+	config := f.plannedNote("Note", "secret")
+	config.OrganizationId = types.StringNull()
+	config.CollectionIDs = nil
+
+	resp := f.modifyPlan(t, config)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var planned SecureNote
+	if diags := resp.Plan.Get(context.Background(), &planned); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !planned.OrganizationId.IsNull() || planned.CollectionIDs != nil {
+		t.Errorf("expected a personal secure note, got %#v", planned)
+	}
+
+	state := f.create(t, planned)
+	created := getSecureNote(t, state)
+	if !created.OrganizationId.IsNull() {
+		t.Errorf("expected organization_id to stay null, got %#v", created.OrganizationId)
+	}
+	for name, state := range map[string]tfsdk.State{"created": state, "read": f.read(t, state).State} {
+		var collections types.Set
+		if diags := state.GetAttribute(context.Background(), path.Root("collection_ids"), &collections); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !collections.IsNull() {
+			t.Errorf("expected the collections of the %s personal secure note to be null, got %s", name, collections)
+		}
+	}
+	if item, _ := f.vault.GetItem(context.Background(), created.ID.ValueString()); item.OrganizationId != "" {
+		t.Errorf("expected the secure note to be in the personal vault, got %s", item.OrganizationId)
+	}
+
+	config.CollectionIDs = []string{f.collection.ID}
+	if resp := f.modifyPlan(t, config); !resp.Diagnostics.HasError() {
+		t.Error("expected an error for collections without organization")
+	}
+}
+
+func TestSecureNoteMovesToOrganization(t *testing.T) {
+	f := newSecureNoteFixture(t)
+
+	personal := f.plannedNote("Note", "secret")
+	personal.OrganizationId = types.StringValue("")
+	personal.CollectionIDs = nil
+	state := f.create(t, personal)
+	created := getSecureNote(t, state)
+
+	planned := created
+	planned.OrganizationId = types.StringValue(f.org.ID)
+	planned.CollectionIDs = []string{f.collection.ID}
+	resp := f.modifyUpdatePlanResponse(t, state, planned)
+	if resp.Diagnostics.HasError() || len(resp.Diagnostics) != 1 {
+		t.Errorf("expected a single warning, got %v", resp.Diagnostics)
+	}
+	if len(resp.RequiresReplace) != 0 {
+		t.Errorf("expected an update in place, got replacements of %v", resp.RequiresReplace)
+	}
+
+	updateResp := resource.UpdateResponse{State: state}
+	f.resource.Update(
+		context.Background(),
+		resource.UpdateRequest{State: state, Plan: resp.Plan},
+		&updateResp,
+	)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", updateResp.Diagnostics)
+	}
+
+	item, err := f.vault.GetItem(context.Background(), created.ID.ValueString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.OrganizationId != f.org.ID || !sameElements(item.CollectionIDs, []string{f.collection.ID}) {
+		t.Errorf("expected the secure note to move to %s/%s, got %#v", f.org.ID, f.collection.ID, item)
+	}
+}
+
+func TestSecureNoteLeavingOrganizationIsReplaced(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	other := f.vault.AddOrganization("Other")
+	otherCollection := f.vault.AddCollection(other.ID, "Other")
+
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	organizationPath := path.Root("organization_id")
+
+	for name, update := range map[string]func(note *SecureNote){
+		"personal vault": func(note *SecureNote) {
+			note.OrganizationId = types.StringValue("")
+			note.CollectionIDs = nil
+		},
+		"other organization": func(note *SecureNote) {
+			note.OrganizationId = types.StringValue(other.ID)
+			note.CollectionIDs = []string{otherCollection.ID}
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			planned := getSecureNote(t, state)
+			update(&planned)
+
+			resp := f.modifyUpdatePlanResponse(t, state, planned)
+			if resp.Diagnostics.HasError() || len(resp.Diagnostics) != 1 {
+				t.Errorf("expected a single warning, got %v", resp.Diagnostics)
+			}
+			if len(resp.RequiresReplace) != 1 || !resp.RequiresReplace[0].Equal(organizationPath) {
+				t.Errorf("expected organization_id to require a replacement, got %v", resp.RequiresReplace)
+			}
+		})
+	}
+}
+
+func TestSecureNotePlanChecksOwnership(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	other := f.vault.AddCollection(f.vault.AddOrganization("Other").ID, "Other")
+	readOnly := f.vault.AddCollection(f.org.ID, "Read-only")
+	f.vault.SetCollectionReadOnly(readOnly.ID, true)
+
+	collectionPath := func(id string) path.Path {
+		return path.Root("collection_ids").AtSetValue(types.StringValue(id))
+	}
+
+	tests := map[string]struct {
+		update func(note *SecureNote)
+		path   path.Path
+	}{
+		"unknown organization": {
+			update: func(note *SecureNote) { note.OrganizationId = types.StringValue(newUUID()) },
+			path:   path.Root("organization_id"),
+		},
+		"collection of another organization": {
+			update: func(note *SecureNote) { note.CollectionIDs = []string{f.collection.ID, other.ID} },
+			path:   collectionPath(other.ID),
+		},
+		"read-only collection": {
+			update: func(note *SecureNote) { note.CollectionIDs = []string{readOnly.ID} },
+			path:   collectionPath(readOnly.ID),
+		},
+		"unknown folder": {
+			update: func(note *SecureNote) { note.FolderID = types.StringValue(newUUID()) },
+			path:   path.Root("folder_id"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := f.plannedNote("Note", "secret")
+			test.update(&config)
+
+			resp := f.modifyPlan(t, config)
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected the plan to be rejected")
+			}
+			for _, d := range resp.Diagnostics {
+				withPath, ok := d.(diag.DiagnosticWithPath)
+				if !ok || !withPath.Path().Equal(test.path) {
+					t.Errorf("expected an error on %s, got %v", test.path, d)
+				}
+			}
+		})
+	}
+
+	if resp := f.modifyPlan(t, f.plannedNote("Note", "secret")); resp.Diagnostics.HasError() {
+		t.Errorf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+func TestSecureNoteReadOnlyProvider(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	state := f.create(t, f.plannedNote("Note", "secret"))
+	f.resource.p.readOnly = true
+
+	if resp := f.modifyPlan(t, f.plannedNote("Other note", "secret")); !resp.Diagnostics.HasError() {
+		t.Error("expected planning a new secure note to fail")
+	}
+
+	createResp := resource.CreateResponse{State: tfsdk.State{Schema: f.schema}}
+	f.resource.Create(
+		context.Background(),
+		resource.CreateRequest{Plan: f.plan(t, f.plannedNote("Other note", "secret"))},
+		&createResp,
+	)
+	if !createResp.Diagnostics.HasError() {
+		t.Error("expected creating a secure note to fail")
+	}
+
+	deleteResp := resource.DeleteResponse{State: state}
+	f.resource.Delete(context.Background(), resource.DeleteRequest{State: state}, &deleteResp)
+	if !deleteResp.Diagnostics.HasError() {
+		t.Error("expected deleting a secure note to fail")
+	}
+
+	if len(f.vault.Items()) != 1 || f.vault.Items()[0].InTrash() {
+		t.Error("expected the vault to be left untouched")
+	}
+
+	// Reading still works
+	if read := f.read(t, state); read.State.Raw.IsNull() {
+		t.Error("expected the secure note to stay in state")
+	}
+}
+
+func TestSecureNoteDeletionProtection(t *testing.T) {
+	f := newSecureNoteFixture(t)
+
+	planned := f.plannedNote("Note", "secret")
+	planned.DeletionProtection = types.BoolValue(true)
+	state := f.create(t, planned)
+
+	destroyResp := resource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: f.schema}}
+	f.resource.ModifyPlan(
+		context.Background(),
+		resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Raw: tftypes.NewValue(f.schema.Type().TerraformType(context.Background()), nil), Schema: f.schema},
+			Plan:   tfsdk.Plan{Raw: tftypes.NewValue(f.schema.Type().TerraformType(context.Background()), nil), Schema: f.schema},
+			State:  state,
+		},
+		&destroyResp,
+	)
+	if !destroyResp.Diagnostics.HasError() {
+		t.Error("expected planning the deletion of a protected secure note to fail")
+	}
+
+	deleteResp := resource.DeleteResponse{State: state}
+	f.resource.Delete(context.Background(), resource.DeleteRequest{State: state}, &deleteResp)
+	if !deleteResp.Diagnostics.HasError() {
+		t.Fatal("expected deleting a protected secure note to fail")
+	}
+	if f.vault.Items()[0].InTrash() {
+		t.Error("expected the protected secure note to be left untouched")
+	}
+
+	unprotected := getSecureNote(t, state)
+	unprotected.DeletionProtection = types.BoolValue(false)
+	state = f.state(t, unprotected)
+
+	deleteResp = resource.DeleteResponse{State: state}
+	f.resource.Delete(context.Background(), resource.DeleteRequest{State: state}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", deleteResp.Diagnostics)
+	}
+}
+
+func (f *secureNoteFixture) importState(id string) resource.ImportStateResponse {
+	resp := resource.ImportStateResponse{
+		State: tfsdk.State{Raw: tftypes.NewValue(f.schema.Type().TerraformType(context.Background()), nil), Schema: f.schema},
+	}
+	f.resource.ImportState(context.Background(), resource.ImportStateRequest{ID: id}, &resp)
+	return resp
+}
+
+func TestSecureNoteImportByID(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	folder := f.vault.AddFolder("Folder")
+
+	item, err := f.vault.CreateItem(context.Background(), ItemCreate{
+		OrganizationId: f.org.ID,
+		CollectionIDs:  []string{f.collection.ID},
+		FolderID:       &folder.ID,
+		Type:           secureNoteItemType,
+		Name:           "Note",
+		Notes:          "secret",
+		Favorite:       true,
+		Reprompt:       1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp := f.importState(item.ID)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	imported := getSecureNote(t, resp.State)
+	if imported.ID.ValueString() != item.ID || imported.Notes.ValueString() != "secret" || imported.OrganizationId.ValueString() != f.org.ID {
+		t.Errorf("unexpected imported secure note %#v", imported)
+	}
+	if len(imported.CollectionIDs) != 1 || imported.CollectionIDs[0] != f.collection.ID {
+		t.Errorf("expected the collections of the item, got %v", imported.CollectionIDs)
+	}
+	if imported.FolderID.ValueString() != folder.ID || !imported.Favorite.ValueBool() || !imported.Reprompt.ValueBool() {
+		t.Errorf("expected the folder, favorite and reprompt of the item, got %#v", imported)
+	}
+	if !imported.DeletionProtection.IsNull() || !imported.Timeouts.IsNull() {
+		t.Errorf("expected the Terraform only attributes to be null, got %#v", imported)
+	}
+
+	// The refresh following the import must not change anything
+	if refreshed := f.read(t, resp.State).State; !refreshed.Raw.Equal(resp.State.Raw) {
+		t.Errorf("expected the refresh to keep the imported state, got %v", refreshed.Raw)
+	}
+}
+
+func TestSecureNoteImportLeavesDefaultsNull(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	state := f.create(t, f.plannedNote("Note", "secret"))
+
+	resp := f.importState(getSecureNote(t, state).ID.ValueString())
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	// Same as the state of the secure note created without folder, favorite nor reprompt
+	if !resp.State.Raw.Equal(state.Raw) {
+		t.Errorf("expected the imported state to be\n%v\ngot\n%v", state.Raw, resp.State.Raw)
+	}
+}
+func TestSecureNoteImportPlansNoChangeWithFalseDefaults(t *testing.T) {
+	ctx := context.Background()
+	f := newSecureNoteFixture(t)
+	imported := f.importState(getSecureNote(t, f.create(t, f.plannedNote("Note", "secret"))).ID.ValueString())
+	if imported.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", imported.Diagnostics)
+	}
+
+	server, err := providerserver.NewProtocol6WithError(vaultProvider{&f.resource.p})()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{}); err != nil || len(resp.Diagnostics) > 0 {
+		t.Fatalf("could not configure the provider: %v %v", err, resp.Diagnostics)
+	}
+
+	objectType := f.schema.Type().TerraformType(ctx)
+	prior, err := tfprotov6.NewDynamicValue(objectType, imported.State.Raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Leaving favorite and reprompt out of the configuration or setting them to false is the same
+	for _, value := range []interface{}{nil, false} {
+		configValue, err := tftypes.Transform(imported.State.Raw, func(path *tftypes.AttributePath, current tftypes.Value) (tftypes.Value, error) {
+			switch path.String() {
+			case `AttributeName("object")`, `AttributeName("id")`, `AttributeName("type")`, `AttributeName("revision_date")`:
+				return tftypes.NewValue(current.Type(), nil), nil
+			case `AttributeName("favorite")`, `AttributeName("reprompt")`:
+				return tftypes.NewValue(tftypes.Bool, value), nil
+			}
+			return current, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, err := tfprotov6.NewDynamicValue(objectType, configValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Terraform proposes the configured values, and the prior state for the computed attributes left out
+		proposedValue, err := tftypes.Transform(imported.State.Raw, func(path *tftypes.AttributePath, current tftypes.Value) (tftypes.Value, error) {
+			switch path.String() {
+			case `AttributeName("favorite")`, `AttributeName("reprompt")`:
+				if value != nil {
+					return tftypes.NewValue(tftypes.Bool, value), nil
+				}
+			}
+			return current, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		proposed, err := tfprotov6.NewDynamicValue(objectType, proposedValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		plan, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+			TypeName:         "bitwarden_secure_note",
+			PriorState:       &prior,
+			ProposedNewState: &proposed,
+			Config:           &config,
+		})
+		if err != nil || len(plan.Diagnostics) > 0 {
+			t.Fatalf("could not plan: %v %v", err, plan.Diagnostics)
+		}
+
+		planned, err := plan.PlannedState.Unmarshal(objectType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diffs, _ := imported.State.Raw.Diff(planned); len(diffs) > 0 {
+			t.Errorf("expected no change with favorite and reprompt set to %v, got %v", value, diffs)
+		}
+	}
+}
+
+func TestSecureNoteImportByName(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	other := f.vault.AddCollection(f.org.ID, "Other")
+
+	expected := getSecureNote(t, f.create(t, f.plannedNote("Team/Note", "secret")))
+	elsewhere := f.plannedNote("Team/Note", "elsewhere")
+	elsewhere.CollectionIDs = []string{other.ID}
+	f.create(t, elsewhere)
+	f.create(t, f.plannedNote("Team/Note (copy)", "copy"))
+
+	resp := f.importState(f.org.ID + "/" + f.collection.ID + "/Team/Note")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if imported := getSecureNote(t, resp.State); imported.ID.ValueString() != expected.ID.ValueString() {
+		t.Errorf("expected secure note %s to be imported, got %s", expected.ID.ValueString(), imported.ID.ValueString())
+	}
+}
+
+func TestSecureNoteImportPersonalByName(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	f.create(t, f.plannedNote("Note", "organization"))
+
+	personal := f.plannedNote("Note", "personal")
+	personal.OrganizationId = types.StringValue("")
+	personal.CollectionIDs = nil
+	expected := getSecureNote(t, f.create(t, personal))
+
+	resp := f.importState("//Note")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if imported := getSecureNote(t, resp.State); imported.ID.ValueString() != expected.ID.ValueString() {
+		t.Errorf("expected personal secure note %s to be imported, got %s", expected.ID.ValueString(), imported.ID.ValueString())
+	}
+}
+
+func TestSecureNoteImportErrors(t *testing.T) {
+	f := newSecureNoteFixture(t)
+	f.create(t, f.plannedNote("Twin", "one"))
+	f.create(t, f.plannedNote("Twin", "two"))
+	trashed := getSecureNote(t, f.create(t, f.plannedNote("Trashed", "secret")))
+	f.vault.TrashItem(trashed.ID.ValueString())
+
+	for _, id := range []string{
+		f.org.ID + "/" + f.collection.ID + "/Twin",
+		f.org.ID + "/" + f.collection.ID + "/Missing",
+		f.org.ID + "//Twin",
+		trashed.ID.ValueString(),
+		"missing-id",
+		"",
+	} {
+		if resp := f.importState(id); !resp.Diagnostics.HasError() {
+			t.Errorf("expected importing %q to fail", id)
+		}
+	}
+}
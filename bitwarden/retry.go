@@ -0,0 +1,124 @@
+package bitwarden
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RetryPolicy Controls how calls to the bw CLI and bw serve API are retried
+type RetryPolicy struct {
+	MaxAttempts int64
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy Policy used when the provider configuration has no "retry" block
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 10,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+// maxRetryAfter Longest Retry-After honoured, resty clamps the wait to it while Backoff caps itself at MaxDelay
+const maxRetryAfter = time.Hour
+
+// Messages returned by the BitWarden API (relayed by bw serve) when we are being throttled
+var rateLimitMessages = []string{
+	"too many requests",
+	"slow down",
+	"rate limit",
+}
+
+// Backoff Returns how long to wait before the given retry attempt (starting at 0), using a capped
+// exponential backoff with full jitter
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	ceiling := math.Min(float64(p.MaxDelay), float64(p.BaseDelay)*math.Exp2(float64(attempt)))
+	if ceiling <= float64(p.BaseDelay) {
+		return p.BaseDelay
+	}
+
+	return p.BaseDelay + time.Duration(rand.Int63n(int64(ceiling)-int64(p.BaseDelay)))
+}
+
+// configure Applies the policy to a resty client used to talk to bw serve. The Retry-After sent by BitWarden
+// wins over MaxDelay, the request timeout still bounds the whole call.
+func (p RetryPolicy) configure(client *resty.Client) {
+	client.SetRetryCount(int(p.MaxAttempts) - 1).
+		SetRetryWaitTime(p.BaseDelay).
+		SetRetryMaxWaitTime(maxRetryAfter).
+		SetRetryAfter(func(_ *resty.Client, response *resty.Response) (time.Duration, error) {
+			if delay := retryAfter(response); delay > 0 {
+				return delay, nil
+			}
+
+			// resty falls back to its own backoff on 0, which panics without base delay
+			return max(p.Backoff(response.Request.Attempt-1), time.Nanosecond), nil
+		}).
+		AddRetryCondition(func(response *resty.Response, _ error) bool {
+			return shouldRetry(response.Request.Method, response.StatusCode(), string(response.Body()))
+		})
+}
+
+// shouldRetry Retries throttled requests, and the ones bw serve failed to relay unless they may have created
+// something: a POST reaching BitWarden before the gateway failed would be created twice
+func shouldRetry(method string, statusCode int, body string) bool {
+	// Retrying won't make a missing item appear
+	if isNotFound(statusCode, body) {
+		return false
+	}
+
+	if isRateLimited(statusCode, body) {
+		return true
+	}
+
+	return statusCode == http.StatusBadGateway && method != http.MethodPost
+}
+
+// isRateLimited Detects throttling, either from the status code or from the error message BitWarden sent back. Only
+// bad requests and the output of failed bw commands (status code 0) are searched for those messages: the body of a
+// successful response is the item itself, whose notes may well mention a rate limit.
+func isRateLimited(statusCode int, body string) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if statusCode != http.StatusBadRequest && statusCode != 0 {
+		return false
+	}
+
+	body = strings.ToLower(body)
+	for _, message := range rateLimitMessages {
+		if strings.Contains(body, message) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retryAfter Parses the Retry-After header of a throttled response, returns 0 when there is none
+func retryAfter(response *resty.Response) time.Duration {
+	if response == nil || response.RawResponse == nil {
+		return 0
+	}
+
+	header := response.Header().Get("Retry-After")
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
@@ -0,0 +1,123 @@
+package bitwarden
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	for _, test := range []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{"first attempt waits the base delay", policy, 0, time.Second, time.Second},
+		{"second attempt doubles the ceiling", policy, 1, time.Second, 2 * time.Second},
+		{"third attempt doubles it again", policy, 2, time.Second, 4 * time.Second},
+		{"later attempts are capped", policy, 20, time.Second, 5 * time.Second},
+		{"equal delays don't jitter", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Second}, 5, time.Second, time.Second},
+		{"no delay", RetryPolicy{}, 3, 0, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for range 100 {
+				delay := test.policy.Backoff(test.attempt)
+				if delay < test.min || delay > test.max {
+					t.Fatalf("expected a delay between %s and %s, got %s", test.min, test.max, delay)
+				}
+			}
+		})
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{"too many requests", http.StatusTooManyRequests, "", true},
+		{"bad request relaying throttling", http.StatusBadRequest, `{"message":"Slow down! Too many requests. Try again in 1s."}`, true},
+		{"CLI output", 0, "Rate limit exceeded. Try again later.", true},
+		{"plain bad request", http.StatusBadRequest, `{"message":"The field Name is required."}`, false},
+		{"bad gateway", http.StatusBadGateway, "", false},
+		{"success", http.StatusOK, `{"success":true}`, false},
+		{"success mentioning a rate limit", http.StatusOK, `{"notes":"Too many requests? Slow down, rate limit"}`, false},
+		{"server error mentioning a rate limit", http.StatusInternalServerError, "rate limit", false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := isRateLimited(test.statusCode, test.body); actual != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		method     string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{"throttled read", http.MethodGet, http.StatusTooManyRequests, "", true},
+		{"throttled creation", http.MethodPost, http.StatusTooManyRequests, "", true},
+		{"throttled bad request", http.MethodPut, http.StatusBadRequest, `{"message":"Too many requests"}`, true},
+		{"plain bad request", http.MethodPut, http.StatusBadRequest, `{"message":"The field Name is required."}`, false},
+		{"bad gateway on read", http.MethodGet, http.StatusBadGateway, "", true},
+		{"bad gateway on update", http.MethodPut, http.StatusBadGateway, "", true},
+		{"bad gateway on creation", http.MethodPost, http.StatusBadGateway, "", false},
+		{"not found", http.MethodGet, http.StatusNotFound, "", false},
+		{"not found message", http.MethodGet, http.StatusBadRequest, `{"message":"Not found."}`, false},
+		{"success", http.MethodGet, http.StatusOK, "", false},
+		{"created item mentioning a rate limit", http.MethodPost, http.StatusOK, `{"success":true,"data":{"notes":"Too many requests, slow down"}}`, false},
+		{"read item mentioning a rate limit", http.MethodGet, http.StatusOK, `{"success":true,"data":{"notes":"rate limit"}}`, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := shouldRetry(test.method, test.statusCode, test.body); actual != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		header   string
+		min      time.Duration
+		max      time.Duration
+		response bool
+	}{
+		{"seconds", "120", 2 * time.Minute, 2 * time.Minute, true},
+		{"beyond the max delay", "3600", time.Hour, time.Hour, true},
+		{"date", time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat), 80 * time.Second, 90 * time.Second, true},
+		{"past date", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), -2 * time.Minute, 0, true},
+		{"missing", "", 0, 0, true},
+		{"invalid", "soon", 0, 0, true},
+		{"no response", "120", 0, 0, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var response *resty.Response
+			if test.response {
+				raw := &http.Response{Header: http.Header{}}
+				if test.header != "" {
+					raw.Header.Set("Retry-After", test.header)
+				}
+				response = &resty.Response{RawResponse: raw}
+			}
+
+			if delay := retryAfter(response); delay < test.min || delay > test.max {
+				t.Errorf("expected a delay between %s and %s, got %s", test.min, test.max, delay)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package bitwarden
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+)
+
+// closeRecorder Client recording the deadline it was closed with
+type closeRecorder struct {
+	closed   bool
+	deadline time.Time
+}
+
+func (c *closeRecorder) Close(ctx context.Context) error {
+	c.closed = true
+	c.deadline, _ = ctx.Deadline()
+	return nil
+}
+
+func TestServeShutsDownWhenServingEnds(t *testing.T) {
+	client := &closeRecorder{}
+	stopped := errors.New("stopped")
+
+	t.Cleanup(func() { serveProvider = providerserver.Serve })
+	serveProvider = func(_ context.Context, _ func() tfprovider.Provider, _ providerserver.ServeOpts) error {
+		// Terraform configured the provider, then asked it to stop
+		registerClient(client)
+		if client.closed {
+			t.Error("expected the client to stay open while serving")
+		}
+		return stopped
+	}
+
+	if err := Serve(context.Background(), "registry.terraform.io/novisto/bitwarden"); !errors.Is(err, stopped) {
+		t.Fatalf("expected the error of the server, got %v", err)
+	}
+
+	if !client.closed {
+		t.Fatal("expected the client to be closed once serving ended")
+	}
+	if remaining := time.Until(client.deadline); remaining > 2*time.Second {
+		t.Errorf("expected the cleanup to end before go-plugin kills the plugin, got %s", remaining)
+	}
+	if len(clients) != 0 {
+		t.Errorf("expected the closed clients to be forgotten, got %d", len(clients))
+	}
+}
@@ -0,0 +1,120 @@
+package bitwarden
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// regionServerURLs Servers of the BitWarden cloud, by region
+var regionServerURLs = map[string]string{
+	"US": "https://vault.bitwarden.com",
+	"EU": "https://vault.bitwarden.eu",
+}
+
+// CLIStatus Output of "bw status", also served by bw serve on /status
+type CLIStatus struct {
+	ServerURL *string `json:"serverUrl"`
+	LastSync  *string `json:"lastSync"`
+	UserEmail string  `json:"userEmail"`
+	UserID    string  `json:"userId"`
+	Status    string  `json:"status"`
+}
+
+type serveStatusResponse struct {
+	Data struct {
+		Template CLIStatus `json:"template"`
+	} `json:"data"`
+}
+
+// configureServer Points the private CLI data directory at the configured server, this must happen before login
+func (c *Client) configureServer(ctx context.Context) error {
+	out, err := RunCommand(ctx, c.environment(), c.executable(), "config", "server", c.ServerURL)
+	if err != nil {
+		return fmt.Errorf("error configuring the bitwarden server %s\n%s\n%s", c.ServerURL, out, err)
+	}
+
+	return nil
+}
+
+// checkServer Makes sure the session we are about to use, that the provider didn't log in itself, belongs to
+// the configured server
+func (c *Client) checkServer(ctx context.Context) error {
+	var status CLIStatus
+
+	if c.Port != 0 {
+		resp, err := resty.New().SetBaseURL(c.serveURL()).R().SetContext(ctx).Get("/status")
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("error fetching the status of bitwarden serve\n%s", resp.Body())
+		}
+
+		var decoded serveStatusResponse
+		if err := json.Unmarshal(resp.Body(), &decoded); err != nil {
+			return err
+		}
+		status = decoded.Data.Template
+	} else {
+		out, err := RunCommand(ctx, c.environment(), c.executable(), "status")
+		if err != nil {
+			return fmt.Errorf("error fetching the status of bitwarden\n%s\n%s", out, err)
+		}
+
+		if err := json.Unmarshal([]byte(out), &status); err != nil {
+			return fmt.Errorf("could not parse the output of bw status\n%s\n%s", out, err)
+		}
+	}
+
+	sessionServer := ""
+	if status.ServerURL != nil {
+		sessionServer = *status.ServerURL
+	}
+
+	if !sameServer(sessionServer, c.ServerURL) {
+		return fmt.Errorf(
+			"the existing bitwarden session belongs to %s, not to the configured server %s",
+			displayServer(sessionServer),
+			c.ServerURL,
+		)
+	}
+
+	return nil
+}
+
+// sameServer Compares two server URLs, an empty URL being the default server of the CLI, i.e. the US cloud
+func sameServer(a string, b string) bool {
+	return normalizeServer(a) == normalizeServer(b)
+}
+
+func normalizeServer(server string) string {
+	if server == "" {
+		server = regionServerURLs["US"]
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(server))
+	if err != nil || parsed.Host == "" {
+		return strings.TrimRight(strings.ToLower(server), "/")
+	}
+
+	host := strings.ToLower(parsed.Host)
+	// The CLI accepts both forms for the US cloud
+	if host == "bitwarden.com" {
+		host = "vault.bitwarden.com"
+	}
+
+	return strings.ToLower(parsed.Scheme) + "://" + host + strings.TrimRight(parsed.Path, "/")
+}
+
+func displayServer(server string) string {
+	if server == "" {
+		return "the default server (" + regionServerURLs["US"] + ")"
+	}
+
+	return server
+}
@@ -0,0 +1,62 @@
+package bitwarden
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+)
+
+// shutdownTimeout go-plugin kills the plugin 2 seconds after asking it to stop, cleaning up must be done before
+const shutdownTimeout = 1500 * time.Millisecond
+
+// serveProvider Serves the provider over gRPC until Terraform is done with it, replaced in tests
+var serveProvider = providerserver.Serve
+
+// closer Client holding on to something until the provider shuts down
+type closer interface {
+	Close(ctx context.Context) error
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   []closer
+)
+
+// registerClient Keeps track of a configured client so that Shutdown can clean it up
+func registerClient(c closer) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	clients = append(clients, c)
+}
+
+// Serve Serves the provider until Terraform asks it to stop, then runs Shutdown however the run ended
+func Serve(ctx context.Context, address string) error {
+	defer Shutdown()
+
+	return serveProvider(ctx, New, providerserver.ServeOpts{Address: address})
+}
+
+// Shutdown Releases what the clients configured during this run hold on to (unlocked vaults, sessions, CLI data
+// directories). The clients are closed concurrently, so that a slow one doesn't leave the others to be killed
+// before they are done.
+func Shutdown() {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = c.Close(ctx)
+		}()
+	}
+	wg.Wait()
+	clients = nil
+}
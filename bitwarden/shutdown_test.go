@@ -0,0 +1,112 @@
+package bitwarden_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"terraform-bitwarden-sync/bitwarden"
+	"terraform-bitwarden-sync/internal/bwtest"
+)
+
+func TestCloseLocksExternalServe(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	port, _ := strconv.ParseInt(env.Serve.Port(), 10, 64)
+
+	client, err := bitwarden.NewClient(ctx, bitwarden.ClientConfig{
+		Password:   bwtest.Password,
+		Port:       port,
+		Retry:      bitwarden.RetryPolicy{MaxAttempts: 1},
+		LockOnExit: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !env.Serve.Unlocked() {
+		t.Fatal("expected the vault to be unlocked")
+	}
+
+	if err := client.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if env.Serve.Unlocked() {
+		t.Error("expected the vault to be locked on exit")
+	}
+}
+
+func TestCloseLeavesExternalServeUnlockedByDefault(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	client := newTestClient(t, env)
+
+	if err := client.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if !env.Serve.Unlocked() {
+		t.Error("expected the vault to stay unlocked")
+	}
+}
+
+func TestCLIClientCloseLocks(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+
+	client, err := bitwarden.NewCLIClient(ctx, bitwarden.ClientConfig{Password: bwtest.Password, LockOnExit: true}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if env.Serve.Unlocked() {
+		t.Error("expected the vault to be locked on exit")
+	}
+}
+
+func TestCloseLogsOutOfGlobalSession(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	port, _ := strconv.ParseInt(env.Serve.Port(), 10, 64)
+
+	dataFile := filepath.Join(env.GlobalAppDataDir, "data.json")
+	if err := os.WriteFile(dataFile, []byte(`{"loggedIn":true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := bitwarden.NewClient(ctx, bitwarden.ClientConfig{
+		Password:     bwtest.Password,
+		Port:         port,
+		LogoutOnExit: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(dataFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"loggedIn":false`) {
+		t.Errorf("expected the global session to be logged out, got %s", data)
+	}
+}
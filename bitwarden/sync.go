@@ -0,0 +1,114 @@
+package bitwarden
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SyncMode When the local copy of the vault is synced with the BitWarden server
+type SyncMode string
+
+const (
+	// SyncAlways Syncs before every operation
+	SyncAlways SyncMode = "always"
+	// SyncOncePerRun Syncs before the first operation of the run only
+	SyncOncePerRun SyncMode = "once_per_run"
+	// SyncIfOlderThan Syncs when the last sync, by the provider or anyone else, is older than SyncPolicy.MaxAge
+	SyncIfOlderThan SyncMode = "if_older_than"
+	// SyncNever Leaves syncing to whoever runs bw serve
+	SyncNever SyncMode = "never"
+)
+
+// SyncModes Every supported sync mode
+var SyncModes = []SyncMode{SyncAlways, SyncOncePerRun, SyncIfOlderThan, SyncNever}
+
+// SyncPolicy Controls how often the vault is synced before operations
+type SyncPolicy struct {
+	Mode SyncMode
+	// MaxAge Age of the last sync from which the vault gets synced, only used with SyncIfOlderThan
+	MaxAge time.Duration
+}
+
+// DefaultSyncPolicy Policy used when the provider configuration has no "sync" block
+var DefaultSyncPolicy = SyncPolicy{Mode: SyncOncePerRun}
+
+// resyncOnNotFound Whether an item that is not found is worth a sync and another try: the local copy of the vault
+// may predate the item
+func (p SyncPolicy) resyncOnNotFound() bool {
+	return p.Mode == "" || p.Mode == SyncOncePerRun || p.Mode == SyncIfOlderThan
+}
+
+// syncFunctions How a transport syncs the vault and finds out when it was last synced
+type syncFunctions struct {
+	sync func(ctx context.Context) error
+	// lastSync Returns nil when the vault was never synced
+	lastSync func(ctx context.Context) (*time.Time, error)
+}
+
+// applySyncPolicy Syncs the vault before an operation when the sync policy asks for it
+func (c *Client) applySyncPolicy(ctx context.Context, fns syncFunctions) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	switch c.SyncPolicy.Mode {
+	case SyncNever:
+		return nil
+	case SyncIfOlderThan:
+		lastSync, err := fns.lastSync(ctx)
+		if err != nil {
+			return err
+		}
+		if lastSync != nil && time.Since(*lastSync) < c.SyncPolicy.MaxAge {
+			return nil
+		}
+	case SyncOncePerRun, "":
+		if c.synced {
+			return nil
+		}
+	}
+
+	return c.forceSync(ctx, fns)
+}
+
+// forceSync Syncs the vault whatever the policy, must be called with syncMu held
+func (c *Client) forceSync(ctx context.Context, fns syncFunctions) error {
+	err := fns.sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.synced = true
+	return nil
+}
+
+// withResync Runs an operation on an item and, when the item is not found, syncs the vault and tries once more
+func (c *Client) withResync(ctx context.Context, fns syncFunctions, operation func() error) error {
+	err := operation()
+	if !errors.Is(err, ErrItemNotFound) || !c.SyncPolicy.resyncOnNotFound() {
+		return err
+	}
+
+	c.syncMu.Lock()
+	syncErr := c.forceSync(ctx, fns)
+	c.syncMu.Unlock()
+	if syncErr != nil {
+		return syncErr
+	}
+
+	return operation()
+}
+
+// parseLastSync Parses the lastSync of bw status, which is null for a vault that was never synced
+func parseLastSync(lastSync *string) (*time.Time, error) {
+	if lastSync == nil || *lastSync == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, *lastSync)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
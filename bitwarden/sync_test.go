@@ -0,0 +1,138 @@
+package bitwarden_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"terraform-bitwarden-sync/bitwarden"
+	"terraform-bitwarden-sync/internal/bwtest"
+)
+
+func newSyncTestClient(t *testing.T, env *bwtest.Environment, policy bitwarden.SyncPolicy) *bitwarden.Client {
+	t.Helper()
+
+	port, _ := strconv.ParseInt(env.Serve.Port(), 10, 64)
+	client, err := bitwarden.NewClient(context.Background(), bitwarden.ClientConfig{
+		Password:   bwtest.Password,
+		Port:       port,
+		Retry:      bitwarden.RetryPolicy{MaxAttempts: 1},
+		SyncPolicy: policy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestSyncPolicies(t *testing.T) {
+	for _, test := range []struct {
+		policy bitwarden.SyncPolicy
+		syncs  int
+	}{
+		{bitwarden.SyncPolicy{Mode: bitwarden.SyncAlways}, 3},
+		{bitwarden.SyncPolicy{Mode: bitwarden.SyncOncePerRun}, 1},
+		{bitwarden.SyncPolicy{Mode: bitwarden.SyncIfOlderThan, MaxAge: time.Hour}, 1},
+		{bitwarden.SyncPolicy{Mode: bitwarden.SyncNever}, 0},
+	} {
+		t.Run(string(test.policy.Mode), func(t *testing.T) {
+			ctx := context.Background()
+			env := bwtest.NewEnvironment(t)
+			client := newSyncTestClient(t, env, test.policy)
+
+			item, err := client.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := client.GetItem(ctx, item.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if syncs := env.Vault.SyncCount(); syncs != test.syncs {
+				t.Errorf("expected %d syncs, got %d", test.syncs, syncs)
+			}
+		})
+	}
+}
+
+func TestSyncIfOlderThanExpires(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	client := newSyncTestClient(t, env, bitwarden.SyncPolicy{Mode: bitwarden.SyncIfOlderThan, MaxAge: time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListFolders(ctx); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if syncs := env.Vault.SyncCount(); syncs != 2 {
+		t.Errorf("expected a sync per operation once the last sync is too old, got %d syncs", syncs)
+	}
+}
+
+func TestResyncWhenItemNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	for name, newVault := range map[string]func(*testing.T, *bwtest.Environment) bitwarden.Vault{
+		"serve": func(t *testing.T, env *bwtest.Environment) bitwarden.Vault {
+			return newSyncTestClient(t, env, bitwarden.DefaultSyncPolicy)
+		},
+		"cli": func(t *testing.T, env *bwtest.Environment) bitwarden.Vault {
+			client, err := bitwarden.NewCLIClient(ctx, bitwarden.ClientConfig{Password: bwtest.Password}, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = client.Close(ctx) })
+			return client
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			env := bwtest.NewEnvironment(t)
+			vault := newVault(t, env)
+
+			if _, err := vault.ListFolders(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			// Created from elsewhere after the vault was synced
+			item, err := env.Vault.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			env.Serve.HideUntilSync(item.ID)
+
+			if _, err := vault.GetItem(ctx, item.ID); err != nil {
+				t.Fatalf("expected the item to be found once synced, got %s", err)
+			}
+			if syncs := env.Vault.SyncCount(); syncs != 2 {
+				t.Errorf("expected the item not being found to trigger a sync, got %d syncs", syncs)
+			}
+		})
+	}
+}
+
+func TestNoResyncWithSyncNever(t *testing.T) {
+	ctx := context.Background()
+	env := bwtest.NewEnvironment(t)
+	client := newSyncTestClient(t, env, bitwarden.SyncPolicy{Mode: bitwarden.SyncNever})
+
+	item, err := env.Vault.CreateItem(ctx, bitwarden.ItemCreate{Type: 2, Name: "Note"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.Serve.HideUntilSync(item.ID)
+
+	if _, err := client.GetItem(ctx, item.ID); !errors.Is(err, bitwarden.ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+	if syncs := env.Vault.SyncCount(); syncs != 0 {
+		t.Errorf("expected no sync, got %d", syncs)
+	}
+}
@@ -0,0 +1,30 @@
+package bitwarden
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultTimeout Time given to an operation when its timeout is not configured
+const defaultTimeout = 10 * time.Minute
+
+// timeoutOf Accessor of a timeouts value, such as the Create method of the resource timeouts or the Open method of
+// the ephemeral ones
+type timeoutOf func(ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics)
+
+// nullTimeouts Timeouts of a resource state that wasn't planned from a configuration, such as an imported one
+func nullTimeouts(ctx context.Context) timeouts.Value {
+	return timeouts.Value{Object: types.ObjectNull(timeouts.AttributesAll(ctx).GetType().(timeouts.Type).AttrTypes)}
+}
+
+// withTimeout Derives a context that expires after the configured timeout of the operation, or after defaultTimeout
+// when the timeout is not configured
+func withTimeout(ctx context.Context, operation timeoutOf) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	timeout, diags := operation(ctx, defaultTimeout)
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	return timeoutCtx, cancel, diags
+}
@@ -0,0 +1,87 @@
+package bitwarden
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ephemeraltimeouts "github.com/hashicorp/terraform-plugin-framework-timeouts/ephemeral/timeouts"
+	listtimeouts "github.com/hashicorp/terraform-plugin-framework-timeouts/list/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func nullEphemeralTimeouts(ctx context.Context) ephemeraltimeouts.Value {
+	attributeTypes := ephemeraltimeouts.Attributes(ctx).GetType().(ephemeraltimeouts.Type).AttrTypes
+	return ephemeraltimeouts.Value{Object: types.ObjectNull(attributeTypes)}
+}
+
+func nullListTimeouts(ctx context.Context) listtimeouts.Value {
+	attributeTypes := listtimeouts.Attributes(ctx).GetType().(listtimeouts.Type).AttrTypes
+	return listtimeouts.Value{Object: types.ObjectNull(attributeTypes)}
+}
+
+// configuredTimeouts Resource timeouts with the given create timeout, the other ones left null
+func configuredTimeouts(t *testing.T, create string) timeouts.Value {
+	t.Helper()
+
+	ctx := context.Background()
+	attributeTypes := timeouts.AttributesAll(ctx).GetType().(timeouts.Type).AttrTypes
+	object, diags := types.ObjectValue(attributeTypes, map[string]attr.Value{
+		"create": types.StringValue(create),
+		"read":   types.StringNull(),
+		"update": types.StringNull(),
+		"delete": types.StringNull(),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	return timeouts.Value{Object: object}
+}
+
+func TestWithTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		name     string
+		timeouts timeouts.Value
+		expected time.Duration
+		invalid  bool
+	}{
+		{"configured", configuredTimeouts(t, "30s"), 30 * time.Second, false},
+		{"null", nullTimeouts(ctx), defaultTimeout, false},
+		{"missing from the state", timeouts.Value{}, defaultTimeout, false},
+		{"invalid", configuredTimeouts(t, "soon"), defaultTimeout, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			timeoutCtx, cancel, diags := withTimeout(ctx, test.timeouts.Create)
+			defer cancel()
+
+			if diags.HasError() != test.invalid {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			deadline, ok := timeoutCtx.Deadline()
+			if !ok {
+				t.Fatal("expected a deadline")
+			}
+			if remaining := time.Until(deadline); remaining > test.expected || remaining < test.expected-time.Minute {
+				t.Errorf("expected a deadline in %s, got %s", test.expected, remaining)
+			}
+		})
+	}
+}
+
+func TestWithTimeoutOtherOperations(t *testing.T) {
+	// Only the create timeout is configured, the other operations get the default
+	timeoutCtx, cancel, diags := withTimeout(context.Background(), configuredTimeouts(t, "30s").Delete)
+	defer cancel()
+
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if deadline, _ := timeoutCtx.Deadline(); time.Until(deadline) <= 30*time.Second {
+		t.Errorf("expected the default timeout, got %s", time.Until(deadline))
+	}
+}
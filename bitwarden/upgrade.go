@@ -0,0 +1,108 @@
+package bitwarden
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/samber/lo"
+)
+
+// secureNoteSchemaVersion Version of the "bitwarden_secure_note" schema, stored by Terraform along with the state
+const secureNoteSchemaVersion = 1
+
+// secureNoteV0 Represents the "bitwarden_secure_note" resource as stored in version 0 of the schema, before type was
+// an integer and collection_ids a set
+type secureNoteV0 struct {
+	Object             types.String   `tfsdk:"object"`
+	ID                 types.String   `tfsdk:"id"`
+	OrganizationId     types.String   `tfsdk:"organization_id"`
+	FolderID           types.String   `tfsdk:"folder_id"`
+	Type               types.Number   `tfsdk:"type"`
+	Reprompt           types.Bool     `tfsdk:"reprompt"`
+	Name               types.String   `tfsdk:"name"`
+	Notes              types.String   `tfsdk:"notes"`
+	NotesWOVersion     types.Int64    `tfsdk:"notes_wo_version"`
+	Favorite           types.Bool     `tfsdk:"favorite"`
+	CollectionIDs      []string       `tfsdk:"collection_ids"`
+	RevisionDate       types.String   `tfsdk:"revision_date"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// secureNoteSchemaV0 Schema of the version 0 state. The attributes added without bumping the version are there too,
+// they are null in states written before them.
+func secureNoteSchemaV0(ctx context.Context) *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"object":              schema.StringAttribute{Computed: true},
+			"id":                  schema.StringAttribute{Computed: true},
+			"organization_id":     schema.StringAttribute{Optional: true, Computed: true},
+			"folder_id":           schema.StringAttribute{Optional: true, Computed: true},
+			"type":                schema.NumberAttribute{Computed: true},
+			"reprompt":            schema.BoolAttribute{Optional: true},
+			"name":                schema.StringAttribute{Required: true},
+			"notes":               schema.StringAttribute{Optional: true, Sensitive: true},
+			"notes_wo_version":    schema.Int64Attribute{Optional: true},
+			"favorite":            schema.BoolAttribute{Optional: true},
+			"collection_ids":      schema.ListAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+			"revision_date":       schema.StringAttribute{Computed: true},
+			"deletion_protection": schema.BoolAttribute{Optional: true},
+			"timeouts":            timeouts.AttributesAll(ctx),
+		},
+	}
+}
+
+// upgradeSecureNoteV0 Converts a version 0 state in place, the item in BitWarden is left alone
+func upgradeSecureNoteV0(prior secureNoteV0) SecureNote {
+	note := SecureNote{
+		Object:         prior.Object,
+		ID:             prior.ID,
+		OrganizationId: prior.OrganizationId,
+		FolderID:       prior.FolderID,
+		Type:           types.Int64Null(),
+		Reprompt:       types.BoolValue(prior.Reprompt.ValueBool()),
+		Name:           prior.Name,
+		Notes:          prior.Notes,
+		NotesWO:        types.StringNull(),
+		NotesWOVersion: prior.NotesWOVersion,
+		Favorite:       types.BoolValue(prior.Favorite.ValueBool()),
+		RevisionDate:   prior.RevisionDate,
+		Timeouts:       prior.Timeouts,
+
+		DeletionProtection: prior.DeletionProtection,
+	}
+
+	if !prior.Type.IsNull() && !prior.Type.IsUnknown() {
+		itemType, _ := prior.Type.ValueBigFloat().Int64()
+		note.Type = types.Int64Value(itemType)
+	}
+
+	// Old versions of the bw CLI returned duplicated collection IDs, which a set can't hold
+	if prior.CollectionIDs != nil {
+		note.CollectionIDs = lo.Uniq[string](prior.CollectionIDs)
+	}
+
+	return note
+}
+
+func (r *resourceSecureNote) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: secureNoteSchemaV0(ctx),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior secureNoteV0
+				diags := req.State.Get(ctx, &prior)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				diags = resp.State.Set(ctx, upgradeSecureNoteV0(prior))
+				resp.Diagnostics.Append(diags...)
+			},
+		},
+	}
+}
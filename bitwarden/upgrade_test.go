@@ -0,0 +1,222 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// upgradeSecureNoteState Upgrades a state stored as JSON the way Terraform does, through the provider server
+func upgradeSecureNoteState(t *testing.T, version int64, json string) SecureNote {
+	t.Helper()
+
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New())()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "bitwarden_secure_note",
+		Version:  version,
+		RawState: &tfprotov6.RawState{JSON: []byte(json)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	schemaResp := resource.SchemaResponse{}
+	(&resourceSecureNote{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	raw, err := resp.UpgradedState.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return getSecureNote(t, tfsdk.State{Schema: schemaResp.Schema, Raw: raw})
+}
+
+func TestSecureNoteUpgradeStateFromV0(t *testing.T) {
+	// State written by the first releases, with duplicated collection IDs returned by old bw CLIs
+	note := upgradeSecureNoteState(t, 0, `{
+		"object": "item",
+		"id": "5d3c6bd1-0d7e-4e2c-8a10-ad8d00f6cb12",
+		"organization_id": "df4736bb-2f70-47ac-98cb-ad7401042241",
+		"folder_id": null,
+		"type": 2,
+		"reprompt": null,
+		"name": "Note",
+		"notes": "secret",
+		"favorite": null,
+		"collection_ids": ["d42f510e-6f45-404a-8a70-ad8d00f6cadf", "d42f510e-6f45-404a-8a70-ad8d00f6cadf"],
+		"revision_date": "2021-11-12T10:00:00.000Z"
+	}`)
+
+	if note.Type.ValueInt64() != 2 {
+		t.Errorf("expected type 2, got %v", note.Type)
+	}
+	if len(note.CollectionIDs) != 1 || note.CollectionIDs[0] != "d42f510e-6f45-404a-8a70-ad8d00f6cadf" {
+		t.Errorf("unexpected collection IDs %v", note.CollectionIDs)
+	}
+	if note.ID.ValueString() != "5d3c6bd1-0d7e-4e2c-8a10-ad8d00f6cb12" || note.Notes.ValueString() != "secret" {
+		t.Errorf("unexpected secure note %+v", note)
+	}
+	if !note.DeletionProtection.IsNull() || !note.Timeouts.IsNull() {
+		t.Errorf("expected attributes missing from the state to be null, got %+v", note)
+	}
+}
+
+func TestSecureNoteUpgradeStateFromV0KeepsLaterAttributes(t *testing.T) {
+	// State written before the version was bumped, by releases which already had these attributes
+	note := upgradeSecureNoteState(t, 0, `{
+		"object": "item",
+		"id": "5d3c6bd1-0d7e-4e2c-8a10-ad8d00f6cb12",
+		"organization_id": null,
+		"type": 2,
+		"name": "Note",
+		"notes": null,
+		"notes_wo_version": 3,
+		"collection_ids": [],
+		"revision_date": "2021-11-12T10:00:00.000Z",
+		"deletion_protection": true,
+		"timeouts": {"create": "1m", "read": null, "update": null, "delete": null}
+	}`)
+
+	if note.NotesWOVersion.ValueInt64() != 3 || !note.DeletionProtection.ValueBool() {
+		t.Errorf("unexpected secure note %+v", note)
+	}
+	if create, _ := note.Timeouts.Create(context.Background(), defaultTimeout); create != time.Minute {
+		t.Errorf("unexpected timeouts %+v", note.Timeouts)
+	}
+	if !note.OrganizationId.IsNull() || !note.NotesWO.IsNull() {
+		t.Errorf("expected a personal secure note without write-only notes, got %+v", note)
+	}
+}
+
+func TestSecureNoteUpgradeStateCurrentVersion(t *testing.T) {
+	note := upgradeSecureNoteState(t, secureNoteSchemaVersion, `{
+		"id": "5d3c6bd1-0d7e-4e2c-8a10-ad8d00f6cb12",
+		"type": 2,
+		"name": "Note",
+		"collection_ids": ["d42f510e-6f45-404a-8a70-ad8d00f6cadf"]
+	}`)
+
+	if note.Type.ValueInt64() != 2 || len(note.CollectionIDs) != 1 {
+		t.Errorf("unexpected secure note %+v", note)
+	}
+}
+
+// vaultProvider Provider configured with a given vault instead of the bw CLI
+type vaultProvider struct {
+	*provider
+}
+
+func (p vaultProvider) Configure(_ context.Context, _ tfprovider.ConfigureRequest, resp *tfprovider.ConfigureResponse) {
+	p.directory = newVaultDirectory()
+	p.openEphemeralItems = &openEphemeralItems{}
+	p.configured = true
+	resp.ResourceData = p.provider
+}
+
+func TestSecureNoteUpgradeStateFromBaselinePlansNoChange(t *testing.T) {
+	ctx := context.Background()
+	vault := NewFakeVault()
+	org := vault.AddOrganization("Org")
+	collection := vault.AddCollection(org.ID, "Collection")
+	item := vault.AddItem(Item{
+		OrganizationId: org.ID,
+		Type:           secureNoteItemType,
+		Name:           "Note",
+		Notes:          "secret",
+		CollectionIDs:  []string{collection.ID},
+	})
+
+	server, err := providerserver.NewProtocol6WithError(vaultProvider{&provider{client: vault}})()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{}); err != nil || len(resp.Diagnostics) > 0 {
+		t.Fatalf("could not configure the provider: %v %v", err, resp.Diagnostics)
+	}
+
+	// State exactly as the first release wrote it: collection_ids a list, type a number, and none of the attributes
+	// added since
+	upgraded, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "bitwarden_secure_note",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(fmt.Sprintf(`{
+			"collection_ids": [%q],
+			"favorite": null,
+			"folder_id": null,
+			"id": %q,
+			"name": "Note",
+			"notes": "secret",
+			"object": "item",
+			"organization_id": %q,
+			"reprompt": null,
+			"revision_date": %q,
+			"type": 2
+		}`, collection.ID, item.ID, org.ID, item.RevisionDate))},
+	})
+	if err != nil || len(upgraded.Diagnostics) > 0 {
+		t.Fatalf("could not upgrade the state: %v %v", err, upgraded.Diagnostics)
+	}
+
+	// The configuration of the first release, which had no optional+computed attributes
+	schemaResp := resource.SchemaResponse{}
+	(&resourceSecureNote{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+	prior, err := upgraded.UpgradedState.Unmarshal(objectType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configValue, err := tftypes.Transform(prior, func(path *tftypes.AttributePath, value tftypes.Value) (tftypes.Value, error) {
+		switch path.String() {
+		case `AttributeName("object")`, `AttributeName("id")`, `AttributeName("type")`, `AttributeName("revision_date")`,
+			`AttributeName("favorite")`, `AttributeName("reprompt")`:
+			return tftypes.NewValue(value.Type(), nil), nil
+		}
+		return value, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := tfprotov6.NewDynamicValue(objectType, configValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Terraform proposes the prior state, which the configuration matches
+	plan, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "bitwarden_secure_note",
+		PriorState:       upgraded.UpgradedState,
+		ProposedNewState: upgraded.UpgradedState,
+		Config:           &config,
+	})
+	if err != nil || len(plan.Diagnostics) > 0 {
+		t.Fatalf("could not plan: %v %v", err, plan.Diagnostics)
+	}
+
+	if len(plan.RequiresReplace) > 0 {
+		t.Errorf("expected no replacement, got %v", plan.RequiresReplace)
+	}
+	planned, err := plan.PlannedState.Unmarshal(objectType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs, _ := prior.Diff(planned); len(diffs) > 0 {
+		t.Errorf("expected no change to the upgraded state, got %v", diffs)
+	}
+}
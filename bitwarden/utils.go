@@ -1,21 +1,48 @@
 package bitwarden
 
 import (
-	"math/rand"
+	"bytes"
+	"context"
 	"os/exec"
-	"time"
+	"strings"
+
+	"github.com/samber/lo"
 )
 
-func RunCommand(commandName string, args ...string) (string, error) {
-	shellCmd := exec.Command(commandName, args...)
+// RunCommand Runs a command and returns its combined output, the command is killed if ctx is cancelled.
+// A nil env runs the command with the environment of the provider.
+func RunCommand(ctx context.Context, env []string, commandName string, args ...string) (string, error) {
+	shellCmd := exec.CommandContext(ctx, commandName, args...)
+	shellCmd.Env = env
 	out, err := shellCmd.CombinedOutput()
 
 	return string(out), err
 }
 
-// RandSleep Util to randomly sleep before calling the BW API, as an attempt to avoid getting rate-limited
-func RandSleep(maxSeconds int) {
-	time.Sleep(time.Duration(rand.Intn(maxSeconds)) * time.Second)
+// RunCommandOutput Same as RunCommand, keeping stdout and stderr apart for commands whose output gets parsed
+func RunCommandOutput(ctx context.Context, env []string, commandName string, args ...string) (string, string, error) {
+	return RunCommandInput(ctx, env, "", commandName, args...)
+}
+
+// RunCommandInput Same as RunCommandOutput, writing input to the standard input of the command. Secrets go there
+// rather than in the arguments, which any local user can read.
+func RunCommandInput(
+	ctx context.Context,
+	env []string,
+	input string,
+	commandName string,
+	args ...string,
+) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	shellCmd := exec.CommandContext(ctx, commandName, args...)
+	shellCmd.Env = env
+	shellCmd.Stdin = strings.NewReader(input)
+	shellCmd.Stdout = &stdout
+	shellCmd.Stderr = &stderr
+	err := shellCmd.Run()
+
+	return stdout.String(), stderr.String(), err
 }
 
 func Unique(slice []string) []string {
@@ -32,3 +59,13 @@ func Unique(slice []string) []string {
 	}
 	return uniqSlice
 }
+
+// sameElements Whether two slices hold the same values, whatever their order
+func sameElements(a []string, b []string) bool {
+	a, b = Unique(a), Unique(b)
+	if len(a) != len(b) {
+		return false
+	}
+
+	return len(lo.Intersect[string](a, b)) == len(a)
+}
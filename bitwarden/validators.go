@@ -0,0 +1,64 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// uuidPattern IDs of BitWarden objects are UUIDs, in lower case as printed by bw
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// uuidValidator Checks the syntax of an ID, or of every ID of a list, so that typos are caught before reaching
+// BitWarden
+type uuidValidator struct {
+	// allowEmpty Accepts an empty string, for IDs where it means "none"
+	allowEmpty bool
+}
+
+func (v uuidValidator) Description(_ context.Context) string {
+	if v.allowEmpty {
+		return "value must be empty or a lower case UUID such as \"df4736bb-2f70-47ac-98cb-ad7401042241\""
+	}
+	return "value must be a lower case UUID such as \"df4736bb-2f70-47ac-98cb-ad7401042241\""
+}
+
+func (v uuidValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v uuidValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	v.check(req.Path, req.ConfigValue, &resp.Diagnostics)
+}
+
+func (v uuidValidator) ValidateList(_ context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	for i, elem := range req.ConfigValue.Elements() {
+		v.check(req.Path.AtListIndex(i), elem, &resp.Diagnostics)
+	}
+}
+
+func (v uuidValidator) ValidateSet(_ context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	for _, elem := range req.ConfigValue.Elements() {
+		v.check(req.Path.AtSetValue(elem), elem, &resp.Diagnostics)
+	}
+}
+
+func (v uuidValidator) check(path path.Path, value attr.Value, diags *diag.Diagnostics) {
+	id, ok := value.(types.String)
+	if !ok || id.IsNull() || id.IsUnknown() || uuidPattern.MatchString(id.ValueString()) ||
+		v.allowEmpty && id.ValueString() == "" {
+		return
+	}
+
+	diags.AddAttributeError(
+		path,
+		"Invalid ID",
+		fmt.Sprintf("%q is not a lower case UUID such as \"df4736bb-2f70-47ac-98cb-ad7401042241\"", id.ValueString()),
+	)
+}
@@ -0,0 +1,77 @@
+package bitwarden
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// validateUUID Runs a uuidValidator on a string or a list, as Terraform does for the attributes of that type
+func validateUUID(v uuidValidator, value attr.Value) diag.Diagnostics {
+	ctx := context.Background()
+
+	switch value := value.(type) {
+	case types.List:
+		resp := validator.ListResponse{}
+		v.ValidateList(ctx, validator.ListRequest{Path: path.Root("ids"), ConfigValue: value}, &resp)
+		return resp.Diagnostics
+	case types.Set:
+		resp := validator.SetResponse{}
+		v.ValidateSet(ctx, validator.SetRequest{Path: path.Root("ids"), ConfigValue: value}, &resp)
+		return resp.Diagnostics
+	default:
+		resp := validator.StringResponse{}
+		v.ValidateString(ctx, validator.StringRequest{Path: path.Root("id"), ConfigValue: value.(types.String)}, &resp)
+		return resp.Diagnostics
+	}
+}
+
+func TestUUIDValidator(t *testing.T) {
+	tests := map[string]struct {
+		value attr.Value
+		valid bool
+	}{
+		"uuid":       {value: types.StringValue("df4736bb-2f70-47ac-98cb-ad7401042241"), valid: true},
+		"null":       {value: types.StringNull(), valid: true},
+		"unknown":    {value: types.StringUnknown(), valid: true},
+		"typo":       {value: types.StringValue("df4736bb-2f70-47ac-98cb-ad740104224")},
+		"upper case": {value: types.StringValue("DF4736BB-2F70-47AC-98CB-AD7401042241")},
+		"name":       {value: types.StringValue("Engineering")},
+		"list of uuids": {
+			value: types.ListValueMust(types.StringType, []attr.Value{
+				types.StringValue("d42f510e-6f45-404a-8a70-ad8d00f6cadf"),
+			}),
+			valid: true,
+		},
+		"list with a typo": {value: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("d42f510e-6f45-404a-8a70-ad8d00f6cadf"),
+			types.StringValue("d42f510e"),
+		})},
+		"set with a typo": {value: types.SetValueMust(types.StringType, []attr.Value{
+			types.StringValue("d42f510e"),
+		})},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := validateUUID(uuidValidator{}, test.value)
+			if diags.HasError() == test.valid {
+				t.Errorf("expected valid=%t, got %v", test.valid, diags)
+			}
+		})
+	}
+}
+
+func TestUUIDValidatorAllowEmpty(t *testing.T) {
+	for _, v := range []uuidValidator{{}, {allowEmpty: true}} {
+		diags := validateUUID(v, types.StringValue(""))
+		if diags.HasError() == v.allowEmpty {
+			t.Errorf("expected an empty ID to be valid=%t, got %v", v.allowEmpty, diags)
+		}
+	}
+}
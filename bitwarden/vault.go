@@ -0,0 +1,59 @@
+package bitwarden
+
+import "context"
+
+// Vault Operations the resources need from a BitWarden vault.
+// Client implements it on top of bw serve, FakeVault keeps everything in memory for tests.
+type Vault interface {
+	// Sync Pulls the latest version of the vault from the BitWarden server
+	Sync(ctx context.Context) error
+	// Lock Locks the vault, the next operation unlocks it again
+	Lock(ctx context.Context) error
+
+	CreateItem(ctx context.Context, item ItemCreate) (*Item, error)
+	GetItem(ctx context.Context, id string) (*Item, error)
+	UpdateItem(ctx context.Context, id string, item ItemCreate) (*Item, error)
+	// UpdateItemCollections Replaces the collections of an organization item, editing the item leaves them unchanged
+	UpdateItemCollections(ctx context.Context, id string, collectionIDs []string) (*Item, error)
+	// MoveItem Shares a personal item with an organization, in the given collections
+	MoveItem(ctx context.Context, id string, organizationId string, collectionIDs []string) error
+	// DeleteItem Sends an item to the trash
+	DeleteItem(ctx context.Context, id string) error
+	// RestoreItem Brings an item back from the trash
+	RestoreItem(ctx context.Context, id string) error
+
+	// ListItems Lists the items outside of the trash, filtered by organization and collection when not empty and by
+	// a case-insensitive search on their name
+	ListItems(ctx context.Context, organizationId string, collectionId string, search string) ([]Item, error)
+	ListFolders(ctx context.Context) ([]Folder, error)
+	ListOrganizations(ctx context.Context) ([]Organization, error)
+	// ListCollections Lists the collections of an organization the user has access to
+	ListCollections(ctx context.Context, organizationId string) ([]Collection, error)
+}
+
+var _ Vault = (*Client)(nil)
+
+type Folder struct {
+	Object string `json:"object"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+}
+
+type Organization struct {
+	Object  string `json:"object"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Status  int    `json:"status"`
+	Type    int    `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+type Collection struct {
+	Object         string `json:"object"`
+	ID             string `json:"id"`
+	OrganizationId string `json:"organizationId"`
+	Name           string `json:"name"`
+	ExternalID     string `json:"externalId"`
+	// ReadOnly The user can see the items of the collection but not add or edit any
+	ReadOnly bool `json:"readOnly"`
+}
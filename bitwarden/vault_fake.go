@@ -0,0 +1,490 @@
+package bitwarden
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// bitwardenDateFormat Format of the dates returned by the BitWarden API
+const bitwardenDateFormat = "2006-01-02T15:04:05.000Z"
+
+// FakeVault In-memory Vault mimicking how BitWarden treats items, used to test resources without the bw CLI.
+// It is safe for concurrent use.
+type FakeVault struct {
+	mu            sync.Mutex
+	organizations map[string]Organization
+	collections   map[string]Collection
+	folders       map[string]Folder
+	items         map[string]Item
+	lastRevision  time.Time
+	syncCount     int
+	lockCount     int
+}
+
+var _ Vault = (*FakeVault)(nil)
+
+func NewFakeVault() *FakeVault {
+	return &FakeVault{
+		organizations: map[string]Organization{},
+		collections:   map[string]Collection{},
+		folders:       map[string]Folder{},
+		items:         map[string]Item{},
+	}
+}
+
+// AddOrganization Seeds an organization the user is a member of
+func (v *FakeVault) AddOrganization(name string) Organization {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	organization := Organization{Object: "organization", ID: newUUID(), Name: name, Status: 2, Enabled: true}
+	v.organizations[organization.ID] = organization
+
+	return organization
+}
+
+// AddCollection Seeds a collection of an organization
+func (v *FakeVault) AddCollection(organizationId string, name string) Collection {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	collection := Collection{Object: "collection", ID: newUUID(), OrganizationId: organizationId, Name: name}
+	v.collections[collection.ID] = collection
+
+	return collection
+}
+
+// SetCollectionReadOnly Makes a collection read-only for the user, or writable again
+func (v *FakeVault) SetCollectionReadOnly(id string, readOnly bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if collection, ok := v.collections[id]; ok {
+		collection.ReadOnly = readOnly
+		v.collections[id] = collection
+	}
+}
+
+// AddFolder Seeds a personal folder
+func (v *FakeVault) AddFolder(name string) Folder {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	folder := Folder{Object: "folder", ID: newUUID(), Name: name}
+	v.folders[folder.ID] = folder
+
+	return folder
+}
+
+// TrashItem Simulates an item being deleted from the web vault
+func (v *FakeVault) TrashItem(id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if item, ok := v.items[id]; ok {
+		item.DeletedDate = v.nextRevision()
+		v.items[id] = item
+	}
+}
+
+// PurgeItem Simulates an item being permanently deleted from the web vault
+func (v *FakeVault) PurgeItem(id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.items, id)
+}
+
+// Items Returns a copy of every item in the vault, including the trashed ones
+func (v *FakeVault) Items() []Item {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return lo.Map[Item, Item](lo.Values[string, Item](v.items), func(item Item, _ int) Item {
+		return copyItem(item)
+	})
+}
+
+// AddItem Seeds an item as BitWarden returns it, e.g. a login with custom fields created in the web vault
+func (v *FakeVault) AddItem(item Item) Item {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	item.Object = "item"
+	item.ID = newUUID()
+	item.RevisionDate = v.nextRevision()
+	v.items[item.ID] = copyItem(item)
+
+	return item
+}
+
+// LockCount Number of times the vault was locked
+func (v *FakeVault) LockCount() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.lockCount
+}
+
+func (v *FakeVault) Lock(_ context.Context) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.lockCount++
+	return nil
+}
+
+// SyncCount Number of times the vault was synced
+func (v *FakeVault) SyncCount() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.syncCount
+}
+
+func (v *FakeVault) Sync(ctx context.Context) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	v.syncCount++
+
+	return nil
+}
+
+func (v *FakeVault) CreateItem(ctx context.Context, create ItemCreate) (*Item, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := v.validate(create.OrganizationId, create.CollectionIDs, create.FolderID); err != nil {
+		return nil, err
+	}
+
+	item := Item{Object: "item", ID: newUUID()}
+	applyItemCreate(&item, create)
+	item.OrganizationId = create.OrganizationId
+	item.CollectionIDs = append([]string{}, create.CollectionIDs...)
+	item.RevisionDate = v.nextRevision()
+	v.items[item.ID] = item
+
+	result := copyItem(item)
+	return &result, nil
+}
+
+func (v *FakeVault) GetItem(ctx context.Context, id string) (*Item, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	item, ok := v.items[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, id)
+	}
+
+	result := copyItem(item)
+	return &result, nil
+}
+
+func (v *FakeVault) UpdateItem(ctx context.Context, id string, update ItemCreate) (*Item, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	item, ok := v.items[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, id)
+	}
+
+	// Editing an item never changes its organization, that takes a move
+	if update.OrganizationId != item.OrganizationId {
+		return nil, fmt.Errorf(
+			"item %s belongs to organization %q, it cannot be edited into organization %q",
+			id,
+			item.OrganizationId,
+			update.OrganizationId,
+		)
+	}
+
+	// Nor its collections, those are edited on their own
+	if err := v.validate(item.OrganizationId, item.CollectionIDs, update.FolderID); err != nil {
+		return nil, err
+	}
+
+	applyItemCreate(&item, update)
+	item.RevisionDate = v.nextRevision()
+	v.items[id] = item
+
+	result := copyItem(item)
+	return &result, nil
+}
+
+func (v *FakeVault) UpdateItemCollections(ctx context.Context, id string, collectionIDs []string) (*Item, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	item, ok := v.items[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, id)
+	}
+
+	if item.OrganizationId == "" {
+		return nil, errors.New("personal items cannot be in collections, they have to be moved to an organization")
+	}
+
+	if err := v.validate(item.OrganizationId, collectionIDs, nil); err != nil {
+		return nil, err
+	}
+
+	item.CollectionIDs = append([]string{}, collectionIDs...)
+	item.RevisionDate = v.nextRevision()
+	v.items[id] = item
+
+	result := copyItem(item)
+	return &result, nil
+}
+
+func (v *FakeVault) MoveItem(ctx context.Context, id string, organizationId string, collectionIDs []string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	item, ok := v.items[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+	}
+
+	// BitWarden only shares personal items, an item never leaves its organization
+	if item.OrganizationId != "" {
+		return errors.New("this item already belongs to an organization")
+	}
+
+	if organizationId == "" {
+		return errors.New("an organization is required to move an item")
+	}
+
+	if err := v.validate(organizationId, collectionIDs, nil); err != nil {
+		return err
+	}
+
+	item.OrganizationId = organizationId
+	item.CollectionIDs = append([]string{}, collectionIDs...)
+	item.RevisionDate = v.nextRevision()
+	v.items[id] = item
+
+	return nil
+}
+
+func (v *FakeVault) DeleteItem(ctx context.Context, id string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	item, ok := v.items[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+	}
+
+	// Deleting an item already in the trash removes it for good
+	if item.InTrash() {
+		delete(v.items, id)
+		return nil
+	}
+
+	item.DeletedDate = v.nextRevision()
+	item.RevisionDate = item.DeletedDate
+	v.items[id] = item
+
+	return nil
+}
+
+func (v *FakeVault) RestoreItem(ctx context.Context, id string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	item, ok := v.items[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, id)
+	}
+
+	item.DeletedDate = ""
+	item.RevisionDate = v.nextRevision()
+	v.items[id] = item
+
+	return nil
+}
+
+func (v *FakeVault) ListItems(
+	ctx context.Context,
+	organizationId string,
+	collectionId string,
+	search string,
+) ([]Item, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items := lo.Filter[Item](lo.Values[string, Item](v.items), func(item Item, _ int) bool {
+		return !item.InTrash() &&
+			(organizationId == "" || item.OrganizationId == organizationId) &&
+			(collectionId == "" || lo.Contains[string](item.CollectionIDs, collectionId)) &&
+			strings.Contains(strings.ToLower(item.Name), strings.ToLower(search))
+	})
+
+	return lo.Map[Item, Item](items, func(item Item, _ int) Item {
+		return copyItem(item)
+	}), nil
+}
+
+func (v *FakeVault) ListFolders(ctx context.Context) ([]Folder, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return lo.Values[string, Folder](v.folders), nil
+}
+
+func (v *FakeVault) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return lo.Values[string, Organization](v.organizations), nil
+}
+
+func (v *FakeVault) ListCollections(ctx context.Context, organizationId string) ([]Collection, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return lo.Filter[Collection](lo.Values[string, Collection](v.collections), func(c Collection, _ int) bool {
+		return organizationId == "" || c.OrganizationId == organizationId
+	}), nil
+}
+
+// validate Applies the checks BitWarden does on the ownership of an item, must be called with the lock held
+func (v *FakeVault) validate(organizationId string, collectionIDs []string, folderId *string) error {
+	if folderId != nil {
+		if _, ok := v.folders[*folderId]; !ok {
+			return fmt.Errorf("folder %s not found", *folderId)
+		}
+	}
+
+	if organizationId == "" {
+		if len(collectionIDs) > 0 {
+			return errors.New("personal items cannot be in collections")
+		}
+		return nil
+	}
+
+	if _, ok := v.organizations[organizationId]; !ok {
+		return fmt.Errorf("organization %s not found", organizationId)
+	}
+
+	if len(collectionIDs) == 0 {
+		return errors.New("an organization item must be in at least one collection")
+	}
+
+	for _, collectionId := range collectionIDs {
+		collection, ok := v.collections[collectionId]
+		if !ok {
+			return fmt.Errorf("collection %s not found", collectionId)
+		}
+		if collection.OrganizationId != organizationId {
+			return fmt.Errorf("collection %s does not belong to organization %s", collectionId, organizationId)
+		}
+		if collection.ReadOnly {
+			return fmt.Errorf("you do not have permission to edit collection %s", collectionId)
+		}
+	}
+
+	return nil
+}
+
+// nextRevision Returns a revision date strictly after the previous one, even within the same millisecond
+func (v *FakeVault) nextRevision() string {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if !now.After(v.lastRevision) {
+		now = v.lastRevision.Add(time.Millisecond)
+	}
+	v.lastRevision = now
+
+	return now.Format(bitwardenDateFormat)
+}
+
+// applyItemCreate Copies the user editable fields of a payload to an item
+func applyItemCreate(item *Item, create ItemCreate) {
+	item.Type = create.Type
+	item.Name = create.Name
+	item.Notes = create.Notes
+	item.Favorite = create.Favorite
+	item.Reprompt = create.Reprompt
+	item.FolderID = ""
+	if create.FolderID != nil {
+		item.FolderID = *create.FolderID
+	}
+	if create.SecureNote != nil {
+		item.SecureNote = *create.SecureNote
+	}
+}
+
+func copyItem(item Item) Item {
+	item.CollectionIDs = append([]string{}, item.CollectionIDs...)
+	item.Login.URIs = append([]ItemLoginURI{}, item.Login.URIs...)
+	item.Fields = append([]ItemField{}, item.Fields...)
+	return item
+}
+
+// newUUID Generates a random (version 4) UUID, like the ones BitWarden uses as IDs
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,130 @@
+package bitwarden
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFakeVaultCreateValidatesCollections(t *testing.T) {
+	ctx := context.Background()
+	vault := NewFakeVault()
+	org := vault.AddOrganization("Org")
+	otherOrg := vault.AddOrganization("Other org")
+	otherCollection := vault.AddCollection(otherOrg.ID, "Other collection")
+
+	_, err := vault.CreateItem(ctx, ItemCreate{OrganizationId: org.ID, Name: "note", Type: 2})
+	if err == nil {
+		t.Fatal("expected an error for an organization item without collections")
+	}
+
+	_, err = vault.CreateItem(ctx, ItemCreate{
+		OrganizationId: org.ID,
+		CollectionIDs:  []string{otherCollection.ID},
+		Name:           "note",
+		Type:           2,
+	})
+	if err == nil {
+		t.Fatal("expected an error for a collection of another organization")
+	}
+
+	_, err = vault.CreateItem(ctx, ItemCreate{CollectionIDs: []string{otherCollection.ID}, Name: "note", Type: 2})
+	if err == nil {
+		t.Fatal("expected an error for a personal item in a collection")
+	}
+}
+
+func TestFakeVaultUpdateBumpsRevisionDate(t *testing.T) {
+	ctx := context.Background()
+	vault := NewFakeVault()
+
+	item, err := vault.CreateItem(ctx, ItemCreate{Name: "note", Type: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := vault.UpdateItem(ctx, item.ID, ItemCreate{Name: "renamed", Type: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Name != "renamed" {
+		t.Errorf("expected name to be updated, got %q", updated.Name)
+	}
+	if updated.RevisionDate <= item.RevisionDate {
+		t.Errorf("expected revision date %q to be after %q", updated.RevisionDate, item.RevisionDate)
+	}
+}
+
+func TestFakeVaultMoveItem(t *testing.T) {
+	ctx := context.Background()
+	vault := NewFakeVault()
+	org := vault.AddOrganization("Org")
+	collection := vault.AddCollection(org.ID, "Collection")
+	otherOrg := vault.AddOrganization("Other org")
+	otherCollection := vault.AddCollection(otherOrg.ID, "Other collection")
+
+	item, err := vault.CreateItem(ctx, ItemCreate{Name: "note", Type: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vault.MoveItem(ctx, item.ID, org.ID, []string{otherCollection.ID}); err == nil {
+		t.Fatal("expected an error when moving into a collection of another organization")
+	}
+
+	if err := vault.MoveItem(ctx, item.ID, org.ID, []string{collection.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := vault.GetItem(ctx, item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.OrganizationId != org.ID {
+		t.Errorf("expected item to belong to %s, got %s", org.ID, moved.OrganizationId)
+	}
+
+	if err := vault.MoveItem(ctx, item.ID, otherOrg.ID, []string{otherCollection.ID}); err == nil {
+		t.Fatal("expected an error when moving an item between organizations")
+	}
+}
+
+func TestFakeVaultDeleteAndRestore(t *testing.T) {
+	ctx := context.Background()
+	vault := NewFakeVault()
+
+	item, err := vault.CreateItem(ctx, ItemCreate{Name: "note", Type: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vault.DeleteItem(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	trashed, err := vault.GetItem(ctx, item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trashed.InTrash() {
+		t.Error("expected a deleted item to be in the trash")
+	}
+
+	if err := vault.RestoreItem(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := vault.GetItem(ctx, item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.InTrash() {
+		t.Error("expected a restored item to be out of the trash")
+	}
+
+	vault.PurgeItem(item.ID)
+	if _, err := vault.GetItem(ctx, item.ID); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+}
@@ -7,7 +7,10 @@ terraform {
   required_version = ">= 1.0.3"
 }
 
-provider "bitwarden" {}
+provider "bitwarden" {
+  default_organization_id = "df4736bb-2f70-47ac-98cb-ad7401042241"
+  default_collection_ids  = ["d42f510e-6f45-404a-8a70-ad8d00f6cadf"]
+}
 
 locals {
   notes = {
@@ -43,8 +46,6 @@ locals {
 resource "bitwarden_secure_note" "platform_db_creds_1" {
   for_each = local.notes
 
-  organization_id = "df4736bb-2f70-47ac-98cb-ad7401042241"
-  collection_ids  = ["d42f510e-6f45-404a-8a70-ad8d00f6cadf"]
-  name            = each.key
-  notes           = each.value
+  name  = each.key
+  notes = each.value
 }
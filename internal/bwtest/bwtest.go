@@ -0,0 +1,128 @@
+// Package bwtest Offline test harness for the provider: a fake bw executable and a fake bw serve API
+package bwtest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"terraform-bitwarden-sync/bitwarden"
+)
+
+const (
+	// Password Master password accepted by the fake bw serve of an Environment
+	Password = "correct horse battery staple"
+	// ClientID and ClientSecret API key accepted by "bw login --apikey"
+	ClientID     = "user.00000000-0000-0000-0000-000000000000"
+	ClientSecret = "fake-client-secret"
+	// SessionKey Session key of the fake bw, printed by "bw unlock --raw"
+	SessionKey = "fake-session-key"
+)
+
+var (
+	buildOnce sync.Once
+	buildDir  string
+	buildErr  error
+)
+
+// Environment Everything a test needs to run the provider offline
+type Environment struct {
+	Vault *bitwarden.FakeVault
+	Serve *Serve
+	// GlobalAppDataDir CLI data directory of the user, set in BITWARDENCLI_APPDATA_DIR
+	GlobalAppDataDir string
+}
+
+// NewEnvironment Puts the fake bw on the PATH, starts a fake bw serve and points the provider at it
+// through BW_SERVE_PORT and BW_PASSWORD, everything is cleaned up at the end of the test
+func NewEnvironment(t *testing.T) *Environment {
+	t.Helper()
+
+	InstallFakeBW(t)
+
+	vault := bitwarden.NewFakeVault()
+	serve := NewServe(vault, Password)
+	t.Cleanup(serve.Close)
+
+	env := &Environment{Vault: vault, Serve: serve, GlobalAppDataDir: t.TempDir()}
+
+	t.Setenv("BW_PASSWORD", Password)
+	t.Setenv("BW_SERVE_PORT", serve.Port())
+	t.Setenv("FAKE_BW_SERVE_URL", serve.URL())
+	t.Setenv("BITWARDENCLI_APPDATA_DIR", env.GlobalAppDataDir)
+	t.Setenv("FAKE_BW_PASSWORD", Password)
+	t.Setenv("FAKE_BW_CLIENTID", ClientID)
+	t.Setenv("FAKE_BW_CLIENTSECRET", ClientSecret)
+
+	return env
+}
+
+// UseCLITransport Makes the provider run a bw command per operation with the session key
+func (e *Environment) UseCLITransport(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("BW_SERVE_PORT", "")
+	t.Setenv("BW_TRANSPORT", "cli")
+	t.Setenv("BW_SESSION", SessionKey)
+}
+
+// UseAPIKey Makes the provider log in with the API key and run bw serve itself, instead of using the fake
+// serve directly
+func (e *Environment) UseAPIKey(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("BW_SERVE_PORT", "")
+	t.Setenv("BW_CLIENTID", ClientID)
+	t.Setenv("BW_CLIENTSECRET", ClientSecret)
+}
+
+// InstallFakeBW Builds the fake bw executable (once per test binary) and prepends it to the PATH
+func InstallFakeBW(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("PATH", filepath.Dir(FakeBW(t))+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// FakeBW Builds the fake bw executable (once per test binary) and returns its path
+func FakeBW(t *testing.T) string {
+	t.Helper()
+
+	executable := "bw"
+	if runtime.GOOS == "windows" {
+		executable += ".exe"
+	}
+
+	buildOnce.Do(func() {
+		buildDir, buildErr = os.MkdirTemp("", "fakebw")
+		if buildErr != nil {
+			return
+		}
+
+		cmd := exec.Command(
+			"go", "build",
+			"-o", filepath.Join(buildDir, executable),
+			"terraform-bitwarden-sync/internal/bwtest/fakebw",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			buildErr = &buildError{err: err, output: string(out)}
+		}
+	})
+
+	if buildErr != nil {
+		t.Fatalf("could not build the fake bw executable: %s", buildErr)
+	}
+
+	return filepath.Join(buildDir, executable)
+}
+
+type buildError struct {
+	err    error
+	output string
+}
+
+func (e *buildError) Error() string {
+	return e.err.Error() + "\n" + e.output
+}
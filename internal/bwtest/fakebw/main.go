@@ -0,0 +1,444 @@
+// Command fakebw Stand-in for the bw CLI, installed as "bw" on the PATH of the acceptance tests.
+//
+// Its state (login, server) is kept in a data.json file of the CLI data directory, like the real CLI.
+// "bw serve" forwards to the fake serve of the test, found in FAKE_BW_SERVE_URL.
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// defaultVersion Version reported when FAKE_BW_VERSION is not set
+	defaultVersion = "2024.6.0"
+	// sessionKey Session key printed by "bw unlock --raw", kept in sync with bwtest.SessionKey
+	sessionKey = "fake-session-key"
+)
+
+type state struct {
+	LoggedIn  bool   `json:"loggedIn"`
+	ClientID  string `json:"clientId,omitempty"`
+	ServerURL string `json:"serverUrl,omitempty"`
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	args = withoutFlag(args, "--nointeraction")
+	if len(args) == 0 {
+		return errors.New("fake bw: missing command")
+	}
+
+	switch args[0] {
+	case "--version":
+		version := os.Getenv("FAKE_BW_VERSION")
+		if version == "" {
+			version = defaultVersion
+		}
+		fmt.Println(version)
+		return nil
+	case "login":
+		return login(args[1:])
+	case "logout":
+		return logout()
+	case "config":
+		return config(args[1:])
+	case "status":
+		return status()
+	case "serve":
+		return serve(args[1:])
+	case "unlock":
+		return unlock(args[1:])
+	case "lock":
+		return lock()
+	case "sync", "get", "create", "edit", "delete", "restore", "move", "list":
+		return vaultCommand(args)
+	}
+
+	return fmt.Errorf("fake bw: unsupported command %q", args)
+}
+
+func login(args []string) error {
+	if len(args) != 1 || args[0] != "--apikey" {
+		return fmt.Errorf("fake bw: only \"login --apikey\" is supported, got %q", args)
+	}
+
+	current, err := load()
+	if err != nil {
+		return err
+	}
+	if current.LoggedIn {
+		return errors.New("You are already logged in.")
+	}
+
+	clientId, clientSecret := os.Getenv("BW_CLIENTID"), os.Getenv("BW_CLIENTSECRET")
+	if clientId == "" || clientSecret == "" {
+		return errors.New("BW_CLIENTID and BW_CLIENTSECRET are required.")
+	}
+	if clientId != os.Getenv("FAKE_BW_CLIENTID") || clientSecret != os.Getenv("FAKE_BW_CLIENTSECRET") {
+		return errors.New("client_id or client_secret is incorrect. Try again.")
+	}
+
+	current.LoggedIn = true
+	current.ClientID = clientId
+	if err := save(current); err != nil {
+		return err
+	}
+
+	fmt.Println("You are logged in!")
+	return nil
+}
+
+func logout() error {
+	current, err := load()
+	if err != nil {
+		return err
+	}
+	if !current.LoggedIn {
+		return errors.New("You are not logged in.")
+	}
+
+	current.LoggedIn = false
+	current.ClientID = ""
+	if err := save(current); err != nil {
+		return err
+	}
+
+	fmt.Println("You have logged out.")
+	return nil
+}
+
+func config(args []string) error {
+	if len(args) != 2 || args[0] != "server" {
+		return fmt.Errorf("fake bw: only \"config server <url>\" is supported, got %q", args)
+	}
+
+	current, err := load()
+	if err != nil {
+		return err
+	}
+	if current.LoggedIn {
+		return errors.New("Logout required before server config update.")
+	}
+
+	current.ServerURL = args[1]
+	if err := save(current); err != nil {
+		return err
+	}
+
+	fmt.Println("Saved setting `config`.")
+	return nil
+}
+
+func status() error {
+	current, err := load()
+	if err != nil {
+		return err
+	}
+
+	output := map[string]interface{}{"serverUrl": nil, "lastSync": nil, "status": "unauthenticated"}
+	if current.ServerURL != "" {
+		output["serverUrl"] = current.ServerURL
+	}
+	if current.LoggedIn {
+		output["status"] = "locked"
+		output["userEmail"] = "terraform@example.com"
+	}
+
+	// With the session key, the vault is the one of the fake serve
+	if os.Getenv("BW_SESSION") == sessionKey {
+		data, err := call(http.MethodGet, "/status", nil)
+		if err != nil {
+			return err
+		}
+
+		var serveStatus struct {
+			Template struct {
+				LastSync *string `json:"lastSync"`
+			} `json:"template"`
+		}
+		if err := json.Unmarshal(data, &serveStatus); err != nil {
+			return err
+		}
+		output["status"] = "unlocked"
+		output["lastSync"] = serveStatus.Template.LastSync
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(output)
+}
+
+func serve(args []string) error {
+	if len(args) != 2 || args[0] != "--port" {
+		return fmt.Errorf("fake bw: only \"serve --port <port>\" is supported, got %q", args)
+	}
+
+	target, err := url.Parse(os.Getenv("FAKE_BW_SERVE_URL"))
+	if err != nil || target.Host == "" {
+		return errors.New("fake bw: FAKE_BW_SERVE_URL must point to the fake serve of the test")
+	}
+
+	// Started with the session key, the served vault is unlocked: unlock the fake serve the way it would be
+	if os.Getenv("BW_SESSION") == sessionKey {
+		if err := unlockServe(); err != nil {
+			return err
+		}
+	}
+
+	return http.ListenAndServe("localhost:"+args[1], httputil.NewSingleHostReverseProxy(target))
+}
+
+func unlock(args []string) error {
+	raw := false
+	password := ""
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--raw":
+			raw = true
+		case args[i] == "--passwordenv" && i+1 < len(args):
+			i++
+			password = os.Getenv(args[i])
+		case args[i] == "--passwordfile" && i+1 < len(args):
+			i++
+			content, err := os.ReadFile(args[i])
+			if err != nil {
+				return err
+			}
+			password = strings.TrimRight(string(content), "\r\n")
+		default:
+			return fmt.Errorf("fake bw: unsupported unlock arguments %q", args)
+		}
+	}
+
+	if !raw {
+		return errors.New("fake bw: only \"unlock --raw\" is supported")
+	}
+
+	if password == "" || password != os.Getenv("FAKE_BW_PASSWORD") {
+		return errors.New("Invalid master password.")
+	}
+
+	fmt.Print(sessionKey)
+	return nil
+}
+
+// lock Locks the fake serve, standing for the vault the session key unlocked
+func lock() error {
+	if os.Getenv("BW_SESSION") != sessionKey {
+		return errors.New("Vault is locked.")
+	}
+
+	if _, err := call(http.MethodPost, "/lock", nil); err != nil {
+		return err
+	}
+
+	fmt.Println("Your vault is locked.")
+	return nil
+}
+
+// vaultCommand Runs a command needing an unlocked vault against the fake serve of the test
+func vaultCommand(args []string) error {
+	if os.Getenv("BW_SESSION") != sessionKey {
+		return errors.New("Vault is locked.")
+	}
+
+	// The fake serve has its own lock, unlock it the way the session key would
+	if err := unlockServe(); err != nil {
+		return err
+	}
+
+	method, path, body, err := route(args)
+	if err != nil {
+		return err
+	}
+
+	data, err := call(method, path, body)
+	if err != nil {
+		return err
+	}
+
+	// bw prints lists without their envelope
+	var list struct {
+		Object string          `json:"object"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal(data, &list) == nil && list.Object == "list" {
+		data = list.Data
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+// unlockServe Unlocks the fake serve of the test, which stands for the vault the session key unlocked
+func unlockServe() error {
+	password, _ := json.Marshal(map[string]string{"password": os.Getenv("FAKE_BW_PASSWORD")})
+	_, err := call(http.MethodPost, "/unlock", password)
+	return err
+}
+
+// route Translates a bw command to its bw serve endpoint. Like bw, the commands taking an encoded JSON read it from
+// the standard input when it is left out of the arguments; unlike bw, they only accept it there, so that the tests
+// fail if the provider ever puts the notes of an item on its command line.
+func route(args []string) (string, string, []byte, error) {
+	decode := func() ([]byte, error) {
+		encoded, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	}
+
+	switch {
+	case args[0] == "sync" && len(args) == 1:
+		return http.MethodPost, "/sync", nil, nil
+	case args[0] == "get" && len(args) == 3 && args[1] == "item":
+		return http.MethodGet, "/object/item/" + args[2], nil, nil
+	case args[0] == "create" && len(args) == 2 && args[1] == "item":
+		body, err := decode()
+		return http.MethodPost, "/object/item", body, err
+	case args[0] == "edit" && len(args) == 3 && args[1] == "item":
+		body, err := decode()
+		return http.MethodPut, "/object/item/" + args[2], body, err
+	case args[0] == "edit" && len(args) == 3 && args[1] == "item-collections":
+		body, err := decode()
+		return http.MethodPut, "/object/item-collections/" + args[2], body, err
+	case args[0] == "delete" && len(args) == 3 && args[1] == "item":
+		return http.MethodDelete, "/object/item/" + args[2], nil, nil
+	case args[0] == "restore" && len(args) == 3 && args[1] == "item":
+		return http.MethodPost, "/restore/item/" + args[2], nil, nil
+	case args[0] == "move" && len(args) == 3:
+		body, err := decode()
+		return http.MethodPost, "/move/" + args[1] + "/" + args[2], body, err
+	case args[0] == "list" && len(args) >= 2 && len(args)%2 == 0:
+		query, err := listQuery(args[2:])
+		return http.MethodGet, "/list/object/" + args[1] + "?" + query.Encode(), nil, err
+	}
+
+	return "", "", nil, fmt.Errorf("fake bw: unsupported command %q", args)
+}
+
+// listQuery Translates the filters of "bw list" to the query parameters of bw serve
+func listQuery(flags []string) (url.Values, error) {
+	params := map[string]string{
+		"--organizationid": "organizationId",
+		"--collectionid":   "collectionId",
+		"--search":         "search",
+	}
+
+	query := url.Values{}
+	for i := 0; i < len(flags); i += 2 {
+		param, ok := params[flags[i]]
+		if !ok {
+			return nil, fmt.Errorf("fake bw: unsupported list flag %q", flags[i])
+		}
+		query.Set(param, flags[i+1])
+	}
+
+	return query, nil
+}
+
+// call Calls the fake serve and returns the data of its answer, or its message as an error
+func call(method string, path string, body []byte) (json.RawMessage, error) {
+	request, err := http.NewRequest(method, os.Getenv("FAKE_BW_SERVE_URL")+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	// Kept in sync with the header the fake serve tells the fake bw apart with
+	request.Header.Set("X-Fake-Bw", "1")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var decoded struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if !decoded.Success {
+		return nil, errors.New(decoded.Message)
+	}
+
+	return decoded.Data, nil
+}
+
+func withoutFlag(args []string, flag string) []string {
+	result := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != flag {
+			result = append(result, arg)
+		}
+	}
+
+	return result
+}
+
+// dataFile Location of the state, in the CLI data directory
+func dataFile() (string, error) {
+	dir := os.Getenv("BITWARDENCLI_APPDATA_DIR")
+	if dir == "" {
+		return "", errors.New("fake bw: BITWARDENCLI_APPDATA_DIR must be set")
+	}
+
+	return filepath.Join(dir, "data.json"), nil
+}
+
+func load() (state, error) {
+	var current state
+
+	path, err := dataFile()
+	if err != nil {
+		return current, err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return current, nil
+	}
+	if err != nil {
+		return current, err
+	}
+
+	return current, json.Unmarshal(content, &current)
+}
+
+func save(current state) error {
+	path, err := dataFile()
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o600)
+}
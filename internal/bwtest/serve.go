@@ -0,0 +1,338 @@
+package bwtest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"terraform-bitwarden-sync/bitwarden"
+)
+
+// fakeCLIHeader Header of the requests the fake bw sends itself, rather than forwards from the provider
+const fakeCLIHeader = "X-Fake-Bw"
+
+// Serve Fake "bw serve", implementing the part of its HTTP API the provider uses on top of a FakeVault
+type Serve struct {
+	Vault    *bitwarden.FakeVault
+	Password string
+	// ServerURL Server reported by /status, empty for the default server of the CLI
+	ServerURL string
+
+	mu       sync.Mutex
+	unlocked bool
+	unlocks  int
+	// passwordUnlocks Unlocks the provider sent the master password for
+	passwordUnlocks int
+	hidden          map[string]bool
+	lastSync        time.Time
+	server          *httptest.Server
+}
+
+// NewServe Starts a fake bw serve on a random local port, call Close once done
+func NewServe(vault *bitwarden.FakeVault, password string) *Serve {
+	s := &Serve{Vault: vault, Password: password}
+	s.server = httptest.NewServer(s)
+
+	return s
+}
+
+// URL Base URL of the fake serve, e.g. http://127.0.0.1:1234
+func (s *Serve) URL() string {
+	return s.server.URL
+}
+
+// Port Port on which the fake serve listens
+func (s *Serve) Port() string {
+	return s.server.URL[strings.LastIndex(s.server.URL, ":")+1:]
+}
+
+func (s *Serve) Close() {
+	s.server.Close()
+}
+
+// Unlocked Whether the vault is currently unlocked
+func (s *Serve) Unlocked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.unlocked
+}
+
+// HideUntilSync Makes an item look missing until the next sync, like an item created from elsewhere after the
+// local copy of the vault was last synced
+func (s *Serve) HideUntilSync(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hidden == nil {
+		s.hidden = map[string]bool{}
+	}
+	s.hidden[id] = true
+}
+
+func (s *Serve) isHidden(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.hidden[id]
+}
+
+// Unlocks Number of successful unlocks
+func (s *Serve) Unlocks() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.unlocks
+}
+
+// PasswordUnlocks Number of successful unlocks for which the provider sent the master password to the API, rather
+// than starting bw serve with a session key
+func (s *Serve) PasswordUnlocks() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.passwordUnlocks
+}
+
+type response struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+type listData struct {
+	Object string      `json:"object"`
+	Data   interface{} `json:"data"`
+}
+
+type statusTemplate struct {
+	ServerURL *string `json:"serverUrl"`
+	LastSync  *string `json:"lastSync"`
+	UserEmail string  `json:"userEmail"`
+	UserID    string  `json:"userId"`
+	Status    string  `json:"status"`
+}
+
+func (s *Serve) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/status":
+		s.status(w)
+	case r.Method == http.MethodPost && r.URL.Path == "/unlock":
+		s.unlock(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/lock":
+		s.mu.Lock()
+		s.unlocked = false
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, response{Success: true, Data: map[string]string{"title": "Your vault is locked."}})
+	case !s.Unlocked():
+		writeJSON(w, http.StatusBadRequest, response{Message: "Vault is locked."})
+	case r.Method == http.MethodPost && r.URL.Path == "/sync":
+		s.sync(w, r)
+	case len(segments) == 3 && (s.isHidden(segments[2]) || segments[0] == "move" && s.isHidden(segments[1])):
+		writeJSON(w, http.StatusBadRequest, response{Message: "Not found."})
+	case segments[0] == "object" && len(segments) >= 2 && segments[1] == "item":
+		s.item(w, r, segments[2:])
+	case r.Method == http.MethodPut && segments[0] == "object" && len(segments) == 3 && segments[1] == "item-collections":
+		s.itemCollections(w, r, segments[2])
+	case r.Method == http.MethodPost && segments[0] == "restore" && len(segments) == 3 && segments[1] == "item":
+		s.result(w, nil, s.Vault.RestoreItem(r.Context(), segments[2]))
+	case r.Method == http.MethodPost && segments[0] == "move" && len(segments) == 3:
+		s.move(w, r, segments[1], segments[2])
+	case r.Method == http.MethodGet && segments[0] == "list" && len(segments) == 3 && segments[1] == "object":
+		s.list(w, r, segments[2])
+	default:
+		writeJSON(w, http.StatusNotFound, response{Message: "Not found."})
+	}
+}
+
+func (s *Serve) status(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template := statusTemplate{
+		UserEmail: "terraform@example.com",
+		UserID:    "00000000-0000-0000-0000-000000000000",
+		Status:    "locked",
+	}
+	if s.ServerURL != "" {
+		template.ServerURL = &s.ServerURL
+	}
+	if s.unlocked {
+		template.Status = "unlocked"
+	}
+	if !s.lastSync.IsZero() {
+		lastSync := s.lastSync.Format("2006-01-02T15:04:05.000Z")
+		template.LastSync = &lastSync
+	}
+
+	writeJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    map[string]interface{}{"object": "template", "template": template},
+	})
+}
+
+func (s *Serve) unlock(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Password != s.Password {
+		writeJSON(w, http.StatusBadRequest, response{Message: "Invalid master password."})
+		return
+	}
+
+	s.mu.Lock()
+	s.unlocked = true
+	s.unlocks++
+	if r.Header.Get(fakeCLIHeader) == "" {
+		s.passwordUnlocks++
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    map[string]string{"title": "Your vault is now unlocked!", "raw": SessionKey},
+	})
+}
+
+func (s *Serve) sync(w http.ResponseWriter, r *http.Request) {
+	if err := s.Vault.Sync(r.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.lastSync = time.Now().UTC()
+	s.hidden = nil
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, response{Success: true, Data: map[string]string{"title": "Syncing complete."}})
+}
+
+func (s *Serve) item(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusNotFound, response{Message: "Not found."})
+			return
+		}
+
+		var create bitwarden.ItemCreate
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			writeJSON(w, http.StatusBadRequest, response{Message: err.Error()})
+			return
+		}
+		item, err := s.Vault.CreateItem(r.Context(), create)
+		s.result(w, item, err)
+		return
+	}
+
+	id := rest[0]
+	switch r.Method {
+	case http.MethodGet:
+		item, err := s.Vault.GetItem(r.Context(), id)
+		s.result(w, item, err)
+	case http.MethodPut:
+		var update bitwarden.ItemCreate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeJSON(w, http.StatusBadRequest, response{Message: err.Error()})
+			return
+		}
+		item, err := s.Vault.UpdateItem(r.Context(), id, update)
+		s.result(w, item, err)
+	case http.MethodDelete:
+		s.result(w, nil, s.Vault.DeleteItem(r.Context(), id))
+	default:
+		writeJSON(w, http.StatusNotFound, response{Message: "Not found."})
+	}
+}
+
+func (s *Serve) itemCollections(w http.ResponseWriter, r *http.Request, id string) {
+	var collectionIDs []string
+	if err := json.NewDecoder(r.Body).Decode(&collectionIDs); err != nil {
+		writeJSON(w, http.StatusBadRequest, response{Message: err.Error()})
+		return
+	}
+
+	item, err := s.Vault.UpdateItemCollections(r.Context(), id, collectionIDs)
+	s.result(w, item, err)
+}
+
+func (s *Serve) move(w http.ResponseWriter, r *http.Request, id string, organizationId string) {
+	var collectionIDs []string
+	if err := json.NewDecoder(r.Body).Decode(&collectionIDs); err != nil {
+		writeJSON(w, http.StatusBadRequest, response{Message: err.Error()})
+		return
+	}
+
+	err := s.Vault.MoveItem(r.Context(), id, organizationId, collectionIDs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	item, err := s.Vault.GetItem(r.Context(), id)
+	s.result(w, item, err)
+}
+
+func (s *Serve) list(w http.ResponseWriter, r *http.Request, object string) {
+	var data interface{}
+	var err error
+
+	switch object {
+	case "items":
+		query := r.URL.Query()
+		data, err = s.Vault.ListItems(r.Context(), query.Get("organizationId"), query.Get("collectionId"), query.Get("search"))
+	case "folders":
+		data, err = s.Vault.ListFolders(r.Context())
+	case "organizations":
+		data, err = s.Vault.ListOrganizations(r.Context())
+	case "collections", "org-collections":
+		data, err = s.Vault.ListCollections(r.Context(), r.URL.Query().Get("organizationId"))
+	default:
+		writeJSON(w, http.StatusNotFound, response{Message: "Not found."})
+		return
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response{Success: true, Data: listData{Object: "list", Data: data}})
+}
+
+// result Writes the response of an operation on an item, nil items are answered with no data
+func (s *Serve) result(w http.ResponseWriter, item *bitwarden.Item, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if item == nil {
+		writeJSON(w, http.StatusOK, response{Success: true})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response{Success: true, Data: item})
+}
+
+// writeError bw serve answers every failure with a 400 and a message
+func writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, bitwarden.ErrItemNotFound) {
+		writeJSON(w, http.StatusBadRequest, response{Message: "Not found."})
+		return
+	}
+
+	writeJSON(w, http.StatusBadRequest, response{Message: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
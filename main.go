@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"log"
+
 	"terraform-bitwarden-sync/bitwarden"
 )
 
 func main() {
-	tfsdk.Serve(context.Background(), bitwarden.New, tfsdk.ServeOpts{
-		Name: "bitwarden",
-	})
+	// Logs out and cleans up once Terraform is done with the provider, even if the run failed
+	err := bitwarden.Serve(context.Background(), "registry.terraform.io/novisto/bitwarden")
+	if err != nil {
+		log.Print(err.Error())
+	}
 }